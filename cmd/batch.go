@@ -7,15 +7,30 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
 
 	"github.com/miniscruff/changie/core"
 )
 
 var (
-	errVersionExists       = errors.New("version already exists")
-	errNoChangesNotAllowed = errors.New("no changes found and allow no changes disabled")
+	errVersionExists            = errors.New("version already exists")
+	errNoChangesNotAllowed      = errors.New("no changes found and allow no changes disabled")
+	errInvalidNoneOnly          = errors.New("none only must be one of previous or metadata")
+	errNoneOnlyMetadataRequired = errors.New("metadata values are required to batch none only changes")
+)
+
+// Ways to batch a release when every change has an auto level of none.
+const (
+	noneOnlyPrevious = "previous"
+	noneOnlyMetadata = "metadata"
+
+	// directory in the changes directory, or project directory, for none only records
+	noneOnlyDir = "none-only"
 )
 
 type Batch struct {
@@ -35,15 +50,19 @@ type Batch struct {
 	Meta              []string
 	Force             bool
 	AllowNoChanges    bool
+	AllProjects       bool
+	AllowMajor        bool
+	NoneOnly          string
 
 	// Dependencies
 	TimeNow       core.TimeNow
 	TemplateCache *core.TemplateCache
 
 	// Computed values
-	config  *core.Config // current configuration
-	writer  io.Writer    // writer we are batching to
-	version string       // the version we are bumping to
+	config         *core.Config // current configuration
+	writer         io.Writer    // writer we are batching to
+	version        string       // the version we are bumping to
+	recordNoneOnly bool         // none only changes are recorded under the previous version
 }
 
 func NewBatch(
@@ -75,9 +94,22 @@ Changes are sorted in the following order:
 
 * Components if enabled, in order specified by config.components
 * Kinds if enabled, in order specified by config.kinds
-* Timestamp oldest first`,
-		Args: cobra.ExactArgs(1),
-		RunE: b.Run,
+* Timestamp oldest first
+
+With --all-projects, the next version of every project is calculated and shown
+as a plan before any release notes are written.
+Projects without unreleased changes are skipped, or if --allow-no-changes=false,
+the batch fails before writing anything.
+
+When every unreleased change has an auto level of none, auto exits with code 3 as no
+release is needed. Use --none-only to batch them anyway, either recorded under the previous
+version with "previous" or as the previous version with --metadata values with "metadata".
+Changes recorded under the previous version are written to a separate file in the
+none-only directory, named the same as the version file, so released notes are never
+changed. Recording more changes under the same version merges them into that file.`,
+		Example: `changie batch auto --all-projects`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    b.Run,
 	}
 
 	cmd.Flags().StringVar(
@@ -157,6 +189,26 @@ Changes are sorted in the following order:
 		"",
 		"Specify which project version we are batching",
 	)
+	cmd.Flags().BoolVar(
+		&b.AllProjects,
+		"all-projects",
+		false,
+		"Batch every project, skipping projects without changes unless allow no changes is disabled",
+	)
+	cmd.MarkFlagsMutuallyExclusive("project", "all-projects")
+	cmd.Flags().BoolVar(
+		&b.AllowMajor,
+		"allow-major",
+		false,
+		"Allow auto to bump the major version of 0.x versions when initial development is enabled",
+	)
+	cmd.Flags().StringVar(
+		&b.NoneOnly,
+		"none-only",
+		"",
+		"Batch changes with only an auto level of none under the previous version or a metadata bump, "+
+			"one of previous or metadata",
+	)
 
 	b.Command = cmd
 
@@ -182,11 +234,16 @@ func (b *Batch) getBatchData() (*core.BatchData, error) {
 		b.config,
 		b.TemplateCache,
 		b.version,
+		b.AllowMajor,
 		b.Prerelease,
 		b.Meta,
 		allChanges,
 		b.Project,
 	)
+	if errors.Is(err, core.ErrNoReleaseNeeded) && b.NoneOnly != "" {
+		currentVersion, err = b.noneOnlyVersion(previousVersion)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +253,7 @@ func (b *Batch) getBatchData() (*core.BatchData, error) {
 		Version:         currentVersion.Original(),
 		VersionNoPrefix: currentVersion.String(),
 		PreviousVersion: previousVersion.Original(),
+		NextVersion:     currentVersion.Original(),
 		Major:           int(currentVersion.Major()), //nolint:gosec
 		Minor:           int(currentVersion.Minor()), //nolint:gosec
 		Patch:           int(currentVersion.Patch()), //nolint:gosec
@@ -206,10 +264,138 @@ func (b *Batch) getBatchData() (*core.BatchData, error) {
 	}, nil
 }
 
+// noneOnlyVersion returns the version to batch changes under when every change has an auto
+// level of none, either the previous version or the previous version with metadata.
+func (b *Batch) noneOnlyVersion(previous *semver.Version) (*semver.Version, error) {
+	if b.NoneOnly == noneOnlyPrevious {
+		b.recordNoneOnly = true
+		return previous, nil
+	}
+
+	if len(b.Meta) == 0 {
+		return nil, errNoneOnlyMetadataRequired
+	}
+
+	ver, err := previous.SetMetadata(strings.Join(b.Meta, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ver, nil
+}
+
+func (b *Batch) Run(cmd *cobra.Command, args []string) error {
+	if b.NoneOnly != "" && b.NoneOnly != noneOnlyPrevious && b.NoneOnly != noneOnlyMetadata {
+		return fmt.Errorf("%w: %s", errInvalidNoneOnly, b.NoneOnly)
+	}
+
+	if b.AllProjects {
+		return b.batchAllProjects(cmd, args[0])
+	}
+
+	config, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	err = b.checkUnknownFiles(cmd, config)
+	if err != nil {
+		return err
+	}
+
+	return b.batchVersion(cmd, args[0])
+}
+
+// checkUnknownFiles warns about unknown files in the unreleased directory,
+// allowing the version header and footer files passed as flags.
+func (b *Batch) checkUnknownFiles(cmd *cobra.Command, config *core.Config) error {
+	return checkUnknownFiles(cmd, config, b.IncludeDirs, b.VersionHeaderPath, b.OldHeaderPath, b.VersionFooterPath)
+}
+
+// batchAllProjects batches the next version of every project with changes, after
+// printing the plan of previous and next versions.
+func (b *Batch) batchAllProjects(cmd *cobra.Command, partOrVersion string) error {
+	config, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(config.Projects) == 0 {
+		return errProjectsNotConfigured
+	}
+
+	err = b.checkUnknownFiles(cmd, config)
+	if err != nil {
+		return err
+	}
+
+	plans, err := core.PlanProjects(
+		config, b.TemplateCache, partOrVersion, b.AllowMajor, b.Prerelease, b.Meta, b.IncludeDirs)
+	if err != nil {
+		return err
+	}
+
+	err = writePlanTable(cmd.OutOrStdout(), plans)
+	if err != nil {
+		return err
+	}
+
+	// fail before writing anything if any project is missing changes
+	for _, plan := range plans {
+		if plan.Reason == core.SkipNoChanges && !b.AllowNoChanges {
+			return fmt.Errorf("%w: %s", errNoChangesNotAllowed, plan.Project)
+		}
+	}
+
+	for _, plan := range plans {
+		version := plan.NextVersion
+
+		if plan.Reason == core.SkipNoReleaseNeeded && b.NoneOnly != "" {
+			version = partOrVersion
+		} else if plan.Skipped {
+			continue
+		}
+
+		b.Project = plan.Project
+
+		err = b.batchVersion(cmd, version)
+		if err != nil {
+			return fmt.Errorf("batching %s: %w", plan.Project, err)
+		}
+	}
+
+	return nil
+}
+
+// writePlanTable writes the project plans as a table of project, previous and next version.
+func writePlanTable(writer io.Writer, plans []core.ProjectPlan) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(table, "PROJECT\tPREVIOUS\tNEXT")
+	if err != nil {
+		return err
+	}
+
+	for _, plan := range plans {
+		next := plan.NextVersion
+		if plan.Skipped {
+			next = "skipped, " + plan.Reason
+		}
+
+		_, err = fmt.Fprintf(table, "%s\t%s\t%s\n", plan.Project, plan.PreviousVersion, next)
+		if err != nil {
+			return err
+		}
+	}
+
+	return table.Flush()
+}
+
 //nolint:gocyclo
-func (b *Batch) Run(cmd *cobra.Command, args []string) (err error) {
+func (b *Batch) batchVersion(cmd *cobra.Command, version string) (err error) {
 	// save our version for later use
-	b.version = args[0]
+	b.version = version
+	b.recordNoneOnly = false
 
 	b.config, err = core.LoadConfig()
 	if err != nil {
@@ -226,6 +412,12 @@ func (b *Batch) Run(cmd *cobra.Command, args []string) (err error) {
 
 		b.Project = pc.Key
 
+		// projects can override the config, such as the version extension
+		b.config, err = b.config.ForProject(pc.Key)
+		if err != nil {
+			return err
+		}
+
 		err = os.MkdirAll(filepath.Join(b.config.ChangesDir, b.Project), core.CreateDirMode)
 		if err != nil {
 			return err
@@ -237,27 +429,59 @@ func (b *Batch) Run(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	// only unreleased changes are cleared, not changes already recorded
+	unreleasedChanges := data.Changes
+
 	if b.DryRun {
 		b.writer = cmd.OutOrStdout()
 	} else {
-		var versionFileName string
+		var (
+			version         *semver.Version
+			versionFilePath string
+		)
 
-		versionFileName, err = b.TemplateCache.ExecuteString(b.config.VersionFileFormat, data)
+		version, err = semver.NewVersion(data.Version)
 		if err != nil {
 			return err
 		}
 
-		versionFilePath := filepath.Join(b.config.ChangesDir, b.Project, versionFileName)
+		versionFilePath, err = core.VersionFilePath(b.config, b.Project, version)
+		if err != nil {
+			return err
+		}
 
-		if !b.Force {
-			if exists, existErr := core.FileExists(versionFilePath); exists || existErr != nil {
-				return fmt.Errorf("%w: %v", errVersionExists, versionFilePath)
+		if b.recordNoneOnly {
+			versionFilePath, err = b.noneOnlyRecordPath(versionFilePath)
+			if err != nil {
+				return err
+			}
+
+			data.Changes, err = b.noneOnlyRecordChanges(versionFilePath, data.Changes)
+			if err != nil {
+				return err
 			}
 		}
 
-		versionFile, createErr := os.Create(versionFilePath)
-		if createErr != nil {
-			return createErr
+		err = os.MkdirAll(filepath.Dir(versionFilePath), core.CreateDirMode)
+		if err != nil {
+			return err
+		}
+
+		exists, existErr := core.FileExists(versionFilePath)
+		if existErr != nil {
+			return fmt.Errorf("%w: %v", errVersionExists, versionFilePath)
+		}
+
+		// none only records are rewritten with the recorded and new changes
+		if exists && !b.recordNoneOnly && !b.Force {
+			return fmt.Errorf("%w: %v", errVersionExists, versionFilePath)
+		}
+
+		var versionFile *os.File
+
+		versionFile, err = os.Create(versionFilePath)
+		if err != nil {
+			return err
 		}
 
 		defer func() {
@@ -274,7 +498,53 @@ func (b *Batch) Run(cmd *cobra.Command, args []string) (err error) {
 		b.writer = versionFile
 	}
 
-	err = b.WriteTemplate(
+	err = b.writeVersionFile(data)
+	if err != nil {
+		return err
+	}
+
+	if !b.DryRun && !b.KeepFragments {
+		err = b.ClearUnreleased(
+			unreleasedChanges,
+			b.VersionHeaderPath,
+			b.config.VersionHeaderPath,
+			b.VersionFooterPath,
+			b.config.VersionFooterPath,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !b.DryRun && b.RemovePrereleases {
+		// only chance we fail is already checked above
+		allVers, _ := core.GetFileVersions(b.config, false, b.Project)
+
+		for _, v := range allVers {
+			if v.Prerelease() == "" {
+				continue
+			}
+
+			var versionPath string
+
+			versionPath, err = core.VersionFilePath(b.config, b.Project, v)
+			if err != nil {
+				return err
+			}
+
+			err = os.Remove(versionPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeRelease writes the version, headers, changes and footers of a release.
+func (b *Batch) writeRelease(data *core.BatchData) error {
+	err := b.WriteTemplate(
 		b.config.VersionFormat,
 		b.config.Newlines.BeforeVersion,
 		b.config.Newlines.AfterVersion,
@@ -340,40 +610,91 @@ func (b *Batch) Run(cmd *cobra.Command, args []string) (err error) {
 	_ = core.WriteNewlines(b.writer, b.config.Newlines.EndOfVersion)
 	_ = core.WriteNewlines(b.writer, b.config.Newlines.AfterReleaseNotes)
 
-	if !b.DryRun && !b.KeepFragments {
-		err = b.ClearUnreleased(
-			data.Changes,
-			b.VersionHeaderPath,
-			b.config.VersionHeaderPath,
-			b.VersionFooterPath,
-			b.config.VersionFooterPath,
-		)
+	return nil
+}
+
+// writeVersionFile writes the frontmatter, if enabled, followed by the release notes.
+// None only records always include frontmatter, so more changes can be merged into them.
+func (b *Batch) writeVersionFile(data *core.BatchData) error {
+	if b.config.VersionFrontmatter || b.recordNoneOnly {
+		_, err := b.versionMetadata(data).WriteTo(b.writer)
 		if err != nil {
 			return err
 		}
 	}
 
-	if !b.DryRun && b.RemovePrereleases {
-		// only chance we fail is already checked above
-		allVers, _ := core.GetAllVersions(b.config, false, b.Project)
+	return b.writeRelease(data)
+}
 
-		for _, v := range allVers {
-			if v.Prerelease() == "" {
-				continue
-			}
+// noneOnlyRecordPath returns the path of the none only record of a version file,
+// in the none only directory of the changes or project directory.
+func (b *Batch) noneOnlyRecordPath(versionFilePath string) (string, error) {
+	projectDir := filepath.Join(b.config.ChangesDir, b.Project)
 
-			err = os.Remove(filepath.Join(
-				b.config.ChangesDir,
-				b.Project,
-				v.Original()+"."+b.config.VersionExt,
-			))
-			if err != nil {
-				return err
-			}
+	relPath, err := filepath.Rel(projectDir, versionFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(projectDir, noneOnlyDir, relPath), nil
+}
+
+// noneOnlyRecordChanges returns the changes already recorded in a none only record,
+// from its frontmatter, merged with the new changes.
+func (b *Batch) noneOnlyRecordChanges(recordPath string, changes []core.Change) ([]core.Change, error) {
+	// records always include frontmatter, even if disabled for version files
+	recordCfg := *b.config
+	recordCfg.VersionFrontmatter = true
+
+	metadata, _, err := core.ReadVersionFile(&recordCfg, recordPath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && metadata == nil) {
+		return changes, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range metadata.Fragments {
+		metadata.Fragments[i].KindKey = metadata.Fragments[i].Kind
+	}
+
+	return core.PrepareChanges(b.config, b.Project, append(metadata.Fragments, changes...))
+}
+
+// versionMetadata returns the frontmatter metadata of a new release.
+func (b *Batch) versionMetadata(data *core.BatchData) core.VersionMetadata {
+	metadata := core.VersionMetadata{
+		Time:            data.Time,
+		Project:         b.Project,
+		PreviousVersion: data.PreviousVersion,
+		Changes:         len(data.Changes),
+	}
+
+	for _, change := range data.Changes {
+		// only the values of the original fragment are stored
+		metadata.Fragments = append(metadata.Fragments, core.Change{
+			Project:   change.Project,
+			Projects:  change.Projects,
+			Component: change.Component,
+			Kind:      change.Kind,
+			Body:      change.Body,
+			Time:      change.Time,
+			Custom:    change.Custom,
+		})
+
+		if change.Kind == "" {
+			continue
+		}
+
+		if metadata.Kinds == nil {
+			metadata.Kinds = make(map[string]int)
 		}
+
+		metadata.Kinds[change.Kind]++
 	}
 
-	return nil
+	return metadata
 }
 
 func (b *Batch) WriteTemplate(
@@ -435,12 +756,17 @@ func (b *Batch) WriteChanges(changes []core.Change) error {
 			lastComponent = change.Component
 			lastKind = ""
 
+			componentLabel := lastComponent
+			if cc := b.config.ComponentFromKeyOrLabel(lastComponent); cc != nil {
+				componentLabel = cc.Label
+			}
+
 			err := b.WriteTemplate(
-				b.config.ComponentFormat,
+				b.config.ComponentHeader(lastComponent),
 				b.config.Newlines.BeforeComponent+1,
 				b.config.Newlines.AfterComponent,
 				core.ComponentData{
-					Component: lastComponent,
+					Component: componentLabel,
 					Env:       b.config.EnvVars(),
 				},
 			)
@@ -484,6 +810,27 @@ func (b *Batch) WriteChanges(changes []core.Change) error {
 	return nil
 }
 
+// removeSharedProject removes our project from a shared change fragment, returning whether
+// any projects remain in the fragment.
+func (b *Batch) removeSharedProject(change core.Change) (bool, error) {
+	// load the fragment again as the change may of been modified when loading our changes
+	fragment, err := core.LoadChange(change.Filename)
+	if err != nil {
+		return false, err
+	}
+
+	fragment.Projects = slices.DeleteFunc(fragment.Projects, func(project string) bool {
+		pc, projectErr := b.config.Project(project)
+		return project == b.Project || (projectErr == nil && pc.Key == b.Project)
+	})
+
+	if len(fragment.Projects) == 0 {
+		return false, nil
+	}
+
+	return true, writeChangeTo(&fragment, change.Filename)
+}
+
 func (b *Batch) ClearUnreleased(changes []core.Change, otherFiles ...string) error {
 	var (
 		filesToMove []string
@@ -510,15 +857,41 @@ func (b *Batch) ClearUnreleased(changes []core.Change, otherFiles ...string) err
 	}
 
 	for _, ch := range changes {
+		// shared changes are only removed once all of their projects are batched
+		if len(ch.Projects) > 0 && b.Project != "" {
+			var remaining bool
+
+			remaining, err = b.removeSharedProject(ch)
+			if err != nil {
+				return err
+			}
+
+			if remaining {
+				continue
+			}
+		}
+
 		filesToMove = append(filesToMove, ch.Filename)
 	}
 
+	unreleasedPath := filepath.Join(b.config.ChangesDir, b.config.UnreleasedDir)
+
 	for _, f := range filesToMove {
 		if b.MoveDir != "" {
-			err = os.Rename(
-				f,
-				filepath.Join(b.config.ChangesDir, b.MoveDir, filepath.Base(f)),
-			)
+			// keep any subdirectories of the unreleased directory when moving
+			movePath := filepath.Base(f)
+			if relPath, relErr := filepath.Rel(unreleasedPath, f); relErr == nil && filepath.IsLocal(relPath) {
+				movePath = relPath
+			}
+
+			movePath = filepath.Join(b.config.ChangesDir, b.MoveDir, movePath)
+
+			err = os.MkdirAll(filepath.Dir(movePath), core.CreateDirMode)
+			if err != nil {
+				return err
+			}
+
+			err = os.Rename(f, movePath)
 			if err != nil {
 				return err
 			}
@@ -530,15 +903,16 @@ func (b *Batch) ClearUnreleased(changes []core.Change, otherFiles ...string) err
 		}
 	}
 
-	for _, include := range b.IncludeDirs {
-		fullInclude := filepath.Join(b.config.ChangesDir, include)
+	// remove any subdirectories left empty, but keep the unreleased directory itself
+	err = core.RemoveEmptyDirs(unreleasedPath, false)
+	if err != nil {
+		return err
+	}
 
-		files, _ := os.ReadDir(fullInclude)
-		if len(files) == 0 {
-			err = os.RemoveAll(fullInclude)
-			if err != nil {
-				return err
-			}
+	for _, include := range b.IncludeDirs {
+		err = core.RemoveEmptyDirs(filepath.Join(b.config.ChangesDir, include), true)
+		if err != nil {
+			return err
 		}
 	}
 
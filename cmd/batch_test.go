@@ -84,6 +84,44 @@ func TestBatchCanBatch(t *testing.T) {
 	then.DirectoryFileCount(t, 0, cfg.ChangesDir, cfg.UnreleasedDir)
 }
 
+func TestBatchCanBatchNestedFragments(t *testing.T) {
+	cfg := batchTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{
+		Kind: "added",
+		Body: "A",
+		Time: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+	})
+	writeChangeFile(t, cfg, &core.Change{
+		Kind:     "added",
+		Body:     "B",
+		Time:     time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC),
+		Filename: filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "backend", "b.yaml"),
+	})
+	writeChangeFile(t, cfg, &core.Change{
+		Kind:     "removed",
+		Body:     "C",
+		Time:     time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Filename: filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "frontend", "web", "c.yaml"),
+	})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+
+	verContents := `## v0.2.0
+### added
+* A
+* B
+### removed
+* C`
+
+	then.FileContents(t, verContents, cfg.ChangesDir, "v0.2.0.md")
+	// emptied subdirectories are removed as well
+	then.DirectoryFileCount(t, 0, cfg.ChangesDir, cfg.UnreleasedDir)
+}
+
 func TestBatchCanBatchWithProject(t *testing.T) {
 	cfg := batchTestConfig()
 	cfg.VersionFileFormat = "{{.Version}}-custom-format.md"
@@ -117,6 +155,338 @@ func TestBatchCanBatchWithProject(t *testing.T) {
 	then.DirectoryFileCount(t, 1, cfg.ChangesDir, cfg.UnreleasedDir)
 }
 
+func TestBatchRemovePrereleasesWithVersionFileFormat(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.VersionFileFormat = "release-{{.VersionNoPrefix}}/notes.md"
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "release-0.1.0", "notes.md")
+	then.CreateFile(t, cfg.ChangesDir, "release-0.2.0-rc1", "notes.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A"})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.RemovePrereleases = true
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+
+	then.FileContents(t, "## v0.2.0\n### added\n* A", cfg.ChangesDir, "release-0.2.0", "notes.md")
+	then.FileNotExists(t, cfg.ChangesDir, "release-0.2.0-rc1", "notes.md")
+	then.FileExists(t, cfg.ChangesDir, "release-0.1.0", "notes.md")
+}
+
+func TestBatchWritesVersionFrontmatter(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.VersionFrontmatter = true
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "A", Key: "a"},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	timeNow := func() time.Time {
+		return time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	}
+
+	then.CreateFile(t, cfg.ChangesDir, "a", "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{
+		Kind:    "added",
+		Body:    "A",
+		Project: "a",
+		Time:    time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		Custom:  map[string]string{"Issue": "12"},
+	})
+	writeChangeFile(t, cfg, &core.Change{
+		Kind:    "added",
+		Body:    "B",
+		Project: "a",
+		Time:    time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+	})
+
+	batch := NewBatch(timeNow, core.NewTemplateCache())
+	batch.Project = "a"
+	err := batch.Run(batch.Command, []string{"minor"})
+	then.Nil(t, err)
+
+	verContents := `---
+time: 2026-03-04T10:00:00Z
+project: a
+previousVersion: v0.1.0
+changes: 2
+kinds:
+    added: 2
+fragments:
+    - project: a
+      kind: added
+      body: A
+      time: 2026-03-01T10:00:00Z
+      custom:
+        Issue: "12"
+    - project: a
+      kind: added
+      body: B
+      time: 2026-03-02T10:00:00Z
+---
+## v0.2.0
+### added
+* A
+* B`
+
+	then.FileContents(t, verContents, cfg.ChangesDir, "a", "v0.2.0.md")
+}
+
+func TestBatchCanBatchWithProjectOverrides(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.VersionFileFormat = "{{.Version}}.md"
+	cfg.Projects = []core.ProjectConfig{
+		{
+			Label:        "CLI",
+			Key:          "cli",
+			Kinds:        []core.KindConfig{{Label: "Command"}},
+			ChangeFormat: "- {{.Body}}",
+			VersionExt:   "txt",
+		},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "Command", Body: "A", Project: "cli"})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.Project = "cli"
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+
+	verContents := `## v0.2.0
+### Command
+- A`
+
+	then.FileContents(t, verContents, cfg.ChangesDir, "cli", "v0.2.0.txt")
+}
+
+func TestBatchSharedProjectFragment(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	shared := core.Change{Kind: "added", Body: "shared", Projects: []string{"a", "b"}}
+	writeChangeFile(t, cfg, &shared)
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.Project = "a"
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+
+	then.FileContents(t, "## v0.2.0\n### added\n* shared", cfg.ChangesDir, "a", "v0.2.0.md")
+
+	// only our project is removed from the shared fragment
+	remaining, err := core.LoadChange(shared.Filename)
+	then.Nil(t, err)
+	then.SliceEquals(t, []string{"b"}, remaining.Projects)
+	contents, err := os.ReadFile(shared.Filename)
+	then.Nil(t, err)
+	then.False(t, strings.Contains(string(contents), "kindKey"))
+
+	batch = NewBatch(time.Now, core.NewTemplateCache())
+	batch.Project = "b"
+	err = batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+
+	then.FileContents(t, "## v0.2.0\n### added\n* shared", cfg.ChangesDir, "b", "v0.2.0.md")
+	then.FileNotExists(t, shared.Filename)
+}
+
+func TestBatchAllProjects(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+		{Label: "C", Key: "c"},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "a", "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A", Project: "a"})
+	writeChangeFile(t, cfg, &core.Change{Kind: "removed", Body: "B", Project: "b"})
+
+	builder := strings.Builder{}
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.AllProjects = true
+	batch.SetOut(&builder)
+
+	err := batch.Run(batch.Command, []string{"minor"})
+	then.Nil(t, err)
+
+	plan := `PROJECT  PREVIOUS  NEXT
+a        v0.1.0    v0.2.0
+b        v0.0.0    v0.1.0
+c        v0.0.0    skipped, no changes
+`
+
+	then.Equals(t, plan, builder.String())
+	then.FileContents(t, "## v0.2.0\n### added\n* A", cfg.ChangesDir, "a", "v0.2.0.md")
+	then.FileContents(t, "## v0.1.0\n### removed\n* B", cfg.ChangesDir, "b", "v0.1.0.md")
+	then.FileNotExists(t, cfg.ChangesDir, "c", "v0.1.0.md")
+	then.DirectoryFileCount(t, 0, cfg.ChangesDir, cfg.UnreleasedDir)
+}
+
+func TestBatchAllProjectsFailsWithoutChangesBeforeWriting(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A", Project: "a"})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.AllProjects = true
+	batch.AllowNoChanges = false
+	batch.SetOut(&strings.Builder{})
+
+	err := batch.Run(batch.Command, []string{"minor"})
+	then.Err(t, errNoChangesNotAllowed, err)
+	then.FileNotExists(t, cfg.ChangesDir, "a", "v0.1.0.md")
+	then.DirectoryFileCount(t, 1, cfg.ChangesDir, cfg.UnreleasedDir)
+}
+
+func TestBatchAllProjectsSkipsProjectsWithOnlyNoneChanges(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Kinds = []core.KindConfig{
+		{Label: "added", AutoLevel: core.MinorLevel},
+		{Label: "internal", AutoLevel: core.NoneLevel},
+	}
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	internal := &core.Change{Kind: "internal", Body: "A", Project: "a"}
+	writeChangeFile(t, cfg, internal)
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "B", Project: "b"})
+
+	builder := strings.Builder{}
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.AllProjects = true
+	batch.AllowNoChanges = false
+	batch.SetOut(&builder)
+
+	err := batch.Run(batch.Command, []string{"auto"})
+	then.Nil(t, err)
+	then.Contains(t, "skipped, no release needed", builder.String())
+	then.FileContents(t, "## v0.1.0\n### added\n* B", cfg.ChangesDir, "b", "v0.1.0.md")
+	then.FileExists(t, internal.Filename)
+}
+
+func TestBatchNoReleaseNeeded(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Kinds = []core.KindConfig{
+		{Label: "internal", AutoLevel: core.NoneLevel},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n### added\n* A"), cfg.ChangesDir, "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "internal", Body: "B"})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+
+	err := batch.Run(batch.Command, []string{"auto"})
+	then.Err(t, core.ErrNoReleaseNeeded, err)
+	then.Equals(t, ExitNoReleaseNeeded, ExitCode(err))
+	then.DirectoryFileCount(t, 1, cfg.ChangesDir, cfg.UnreleasedDir)
+}
+
+func TestBatchNoneOnlyRecordsUnderPreviousVersion(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Kinds = []core.KindConfig{
+		{Label: "internal", AutoLevel: core.NoneLevel},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n### added\n* A"), cfg.ChangesDir, "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "internal", Body: "B"})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.NoneOnly = "previous"
+
+	err := batch.Run(batch.Command, []string{"auto"})
+	then.Nil(t, err)
+
+	// recording more changes merges them into the same record
+	writeChangeFile(t, cfg, &core.Change{Kind: "internal", Body: "C"})
+
+	err = batch.Run(batch.Command, []string{"auto"})
+	then.Nil(t, err)
+
+	then.FileContents(t, "## v0.1.0\n### added\n* A", cfg.ChangesDir, "v0.1.0.md")
+	then.DirectoryFileCount(t, 0, cfg.ChangesDir, cfg.UnreleasedDir)
+
+	metadata, notes, err := core.ReadVersionFile(
+		&core.Config{VersionFrontmatter: true},
+		filepath.Join(cfg.ChangesDir, noneOnlyDir, "v0.1.0.md"),
+	)
+	then.Nil(t, err)
+	then.Equals(t, 2, metadata.Changes)
+	then.Equals(t, 2, metadata.Kinds["internal"])
+	then.Equals(t, "## v0.1.0\n### internal\n* B\n* C", string(notes))
+
+	versions, err := core.GetFileVersions(cfg, false, "")
+	then.Nil(t, err)
+	then.SliceLen(t, 1, versions)
+}
+
+func TestBatchNoneOnlyWithMetadata(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Kinds = []core.KindConfig{
+		{Label: "internal", AutoLevel: core.NoneLevel},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "internal", Body: "B"})
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.NoneOnly = "metadata"
+
+	err := batch.Run(batch.Command, []string{"auto"})
+	then.Err(t, errNoneOnlyMetadataRequired, err)
+
+	batch.Meta = []string{"internal"}
+
+	err = batch.Run(batch.Command, []string{"auto"})
+	then.Nil(t, err)
+	then.FileContents(t, "## v0.1.0+internal\n### internal\n* B", cfg.ChangesDir, "v0.1.0+internal.md")
+}
+
+func TestErrorBatchInvalidNoneOnly(t *testing.T) {
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.NoneOnly = "other"
+
+	err := batch.Run(batch.Command, []string{"auto"})
+	then.Err(t, errInvalidNoneOnly, err)
+}
+
+func TestBatchAllProjectsFailsWithoutProjects(t *testing.T) {
+	cfg := batchTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.AllProjects = true
+
+	err := batch.Run(batch.Command, []string{"minor"})
+	then.Err(t, errProjectsNotConfigured, err)
+}
+
 func TestBatchProjectFailsIfUnableToFindProject(t *testing.T) {
 	cfg := batchTestConfig()
 	cfg.Projects = []core.ProjectConfig{
@@ -348,6 +718,8 @@ func TestBatchWithHeadersAndFooters(t *testing.T) {
 	cfg.VersionFooterPath = "f2.md"
 	cfg.HeaderFormat = "header format"
 	cfg.FooterFormat = "footer format"
+	// header and footer files passed as flags are not unknown fragments
+	cfg.StrictFragments = true
 	then.WithTempDirConfig(t, cfg)
 
 	batch := NewBatch(time.Now, core.NewTemplateCache())
@@ -597,7 +969,7 @@ func TestBatchCreateVersionsWithoutKindHeaders(t *testing.T) {
 
 func TestBatchVersionFileWithComponentHeaders(t *testing.T) {
 	cfg := batchTestConfig()
-	cfg.Components = []string{"linker", "compiler"}
+	cfg.Components = []core.ComponentConfig{{Label: "linker"}, {Label: "compiler"}}
 	cfg.ComponentFormat = "## {{.Component}}"
 	cfg.KindFormat = "### {{.Kind}}"
 	cfg.ChangeFormat = "* {{.Body}}"
@@ -631,9 +1003,41 @@ func TestBatchVersionFileWithComponentHeaders(t *testing.T) {
 	then.Equals(t, expected, builder.String())
 }
 
+func TestBatchVersionFileWithComponentConfigs(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.Components = []core.ComponentConfig{
+		{Key: "web", Label: "Frontend"},
+		{Key: "api", Label: "API", Format: "## {{.Component}} (internal)"},
+	}
+	cfg.ComponentFormat = "## {{.Component}}"
+	cfg.KindFormat = ""
+	cfg.ChangeFormat = "* {{.Body}}"
+
+	var builder strings.Builder
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.config = cfg
+	batch.writer = &builder
+
+	changes := []core.Change{
+		{Body: "x", Kind: "added", Component: "web"},
+		{Body: "y", Kind: "added", Component: "api"},
+	}
+
+	err := batch.WriteChanges(changes)
+	then.Nil(t, err)
+
+	expected := `
+## Frontend
+* x
+## API (internal)
+* y`
+	then.Equals(t, expected, builder.String())
+}
+
 func TestBatchVersionFileWithoutComponentHeadersGroupsByKind(t *testing.T) {
 	cfg := batchTestConfig()
-	cfg.Components = []string{"cli", "web"}
+	cfg.Components = []core.ComponentConfig{{Label: "cli"}, {Label: "web"}}
 	cfg.ComponentFormat = ""
 	cfg.KindFormat = "### {{.Kind}}"
 	cfg.ChangeFormat = "* {{.Body}} ({{.Component}})"
@@ -732,6 +1136,28 @@ func TestBatchClearUnreleasedMovesFilesIncludingHeaderIfSpecified(t *testing.T)
 	then.DirectoryFileCount(t, 1, cfg.ChangesDir, cfg.UnreleasedDir)
 }
 
+func TestBatchClearUnreleasedMoveKeepsSubdirectories(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := batchTestConfig()
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.config = cfg
+	batch.MoveDir = "beta"
+
+	change := core.Change{
+		Kind:     "added",
+		Body:     "A",
+		Filename: filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "backend", "a.yaml"),
+	}
+	writeChangeFile(t, cfg, &change)
+
+	err := batch.ClearUnreleased([]core.Change{change})
+	then.Nil(t, err)
+
+	then.FileExists(t, cfg.ChangesDir, "beta", "backend", "a.yaml")
+	then.FileNotExists(t, cfg.ChangesDir, cfg.UnreleasedDir, "backend")
+}
+
 func TestBatchCanAddNewLinesAfterReleaseNotes(t *testing.T) {
 	cfg := batchTestConfig()
 	cfg.Newlines.AfterReleaseNotes = 2
@@ -755,3 +1181,39 @@ func TestBatchCanAddNewLinesAfterReleaseNotes(t *testing.T) {
 	then.FileContents(t, verContents, cfg.ChangesDir, "v0.2.0.md")
 	then.DirectoryFileCount(t, 0, cfg.ChangesDir, cfg.UnreleasedDir)
 }
+
+func TestBatchWarnsAboutUnknownFiles(t *testing.T) {
+	cfg := batchTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A"})
+	then.WriteFile(t, []byte("notes"), cfg.ChangesDir, cfg.UnreleasedDir, "notes.txt")
+
+	var warnings strings.Builder
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+	batch.SetErr(&warnings)
+
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+	then.Equals(t, fmt.Sprintf(
+		"warning: %v: %s\n",
+		core.ErrUnknownFragmentFile,
+		filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "notes.txt"),
+	), warnings.String())
+}
+
+func TestErrorBatchUnknownFilesWhenStrict(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.StrictFragments = true
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A"})
+	then.WriteFile(t, []byte("notes"), cfg.ChangesDir, cfg.UnreleasedDir, "notes.txt")
+
+	batch := NewBatch(time.Now, core.NewTemplateCache())
+
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Err(t, core.ErrUnknownFragmentFile, err)
+	then.FileNotExists(t, cfg.ChangesDir, "v0.2.0.md")
+}
@@ -1,10 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
@@ -12,19 +13,24 @@ import (
 	"github.com/miniscruff/changie/core"
 )
 
+var errDiffRangeRequired = errors.New("a version range, --since or --until is required")
+
 type Diff struct {
 	*cobra.Command
 
 	// CLI args
 	SkipPrereleases bool
 	Project         string
+	Since           string
+	Until           string
+	Order           string
 }
 
 func NewDiff() *Diff {
 	diff := &Diff{}
 
 	cmd := &cobra.Command{
-		Use:   "diff N|>,>=,<,<=version|versiona - versionb|start...end",
+		Use:   "diff [N|>,>=,<,<=version|versiona - versionb|start...end]",
 		Short: "diff outputs the release notes between versions.",
 		Long: `diff outputs to stdout the release notes between two versions.
 
@@ -35,9 +41,17 @@ supports as a comparison then we output the release notes between those two.
 Finally one last option is any of the constraints options from the semver package:
 https://github.com/Masterminds/semver#checking-version-constraints.
 
+Versions can also be filtered by release date with --since and --until, inclusive.
+The release date is read from the version frontmatter if available, otherwise the date
+of the commit that added the version file.
+The argument is optional when filtering by date.
+
+Versions are written in the configured version order, which can be overridden with --order.
+With a date order, a number argument outputs the most recently released versions.
+
 Between versions we also add an amount of newlines specified by the AfterChangelogVersion value.`,
 		Example: `v1.20.0...v1.21.1`,
-		Args:    cobra.MatchAll(cobra.ExactArgs(1)),
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    diff.Run,
 	}
 	cmd.Flags().BoolVarP(
@@ -52,6 +66,24 @@ Between versions we also add an amount of newlines specified by the AfterChangel
 		"",
 		"Specify which project we are interested in",
 	)
+	cmd.Flags().StringVar(
+		&diff.Since,
+		"since",
+		"",
+		"Only include versions released on or after a date, formatted as 2006-01-02",
+	)
+	cmd.Flags().StringVar(
+		&diff.Until,
+		"until",
+		"",
+		"Only include versions released on or before a date, formatted as 2006-01-02",
+	)
+	cmd.Flags().StringVar(
+		&diff.Order,
+		"order",
+		"",
+		"Order of versions, one of descending, ascending, date-descending or date-ascending",
+	)
 
 	diff.Command = cmd
 
@@ -60,7 +92,10 @@ Between versions we also add an amount of newlines specified by the AfterChangel
 
 func (d *Diff) Run(cmd *cobra.Command, args []string) error {
 	writer := cmd.OutOrStdout()
-	versionRange := args[0]
+
+	if len(args) == 0 && d.Since == "" && d.Until == "" {
+		return errDiffRangeRequired
+	}
 
 	config, err := core.LoadConfig()
 	if err != nil {
@@ -76,40 +111,78 @@ func (d *Diff) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		d.Project = pc.Key
+
+		// projects can override the config, such as the version extension
+		config, err = config.ForProject(pc.Key)
+		if err != nil {
+			return err
+		}
 	}
 
-	vers, err := core.GetAllVersions(config, d.SkipPrereleases, d.Project)
+	vers, err := core.GetFileVersions(config, d.SkipPrereleases, d.Project)
 	if err != nil {
 		return err
 	}
 
-	firstOutput := true
+	vers, err = d.filterByDate(config, vers)
+	if err != nil {
+		return err
+	}
 
-	versionCount, err := strconv.ParseInt(versionRange, 10, 64)
-	if err == nil {
-		for i := range min(int(versionCount), len(vers)) {
-			if !firstOutput {
-				err := core.WriteNewlines(writer, config.Newlines.AfterChangelogVersion)
-				if err != nil {
-					return err
-				}
-			}
+	order := d.Order
+	if order == "" {
+		order = config.VersionOrder
+	}
 
-			firstOutput = false
+	// the latest N versions by date can differ from the latest by version, such as
+	// maintenance releases, so date orders are applied before filtering
+	if order == core.VersionOrderDateDescending || order == core.VersionOrderDateAscending {
+		vers, err = core.OrderVersions(config, d.Project, core.VersionOrderDateDescending, vers)
+		if err != nil {
+			return err
+		}
+	}
 
-			versionPath := filepath.Join(
-				config.ChangesDir,
-				d.Project,
-				vers[i].Original()+"."+config.VersionExt,
-			)
+	if len(args) > 0 {
+		vers, err = filterByRange(vers, args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	vers, err = core.OrderVersions(config, d.Project, order, vers)
+	if err != nil {
+		return err
+	}
 
-			err = core.AppendFile(writer, versionPath)
+	for i, ver := range vers {
+		if i > 0 {
+			err = core.WriteNewlines(writer, config.Newlines.AfterChangelogVersion)
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		versionPath, err := core.VersionFilePath(config, d.Project, ver)
+		if err != nil {
+			return err
+		}
+
+		err = core.AppendVersionFile(config, writer, versionPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filterByRange returns the latest N versions if the range is a number,
+// otherwise versions matching the range as a semver constraint.
+func filterByRange(vers []*semver.Version, versionRange string) ([]*semver.Version, error) {
+	versionCount, err := strconv.ParseInt(versionRange, 10, 64)
+	if err == nil {
+		return vers[:min(int(versionCount), len(vers))], nil
 	}
 
 	before, after, found := strings.Cut(versionRange, "...")
@@ -119,34 +192,68 @@ func (d *Diff) Run(cmd *cobra.Command, args []string) error {
 
 	con, err := semver.NewConstraint(versionRange)
 	if err != nil {
-		return fmt.Errorf("version range: %w", err)
+		return nil, fmt.Errorf("version range: %w", err)
 	}
 
+	filtered := make([]*semver.Version, 0, len(vers))
+
 	for _, ver := range vers {
-		if !con.Check(ver) {
-			continue
+		if con.Check(ver) {
+			filtered = append(filtered, ver)
 		}
+	}
 
-		if !firstOutput {
-			err := core.WriteNewlines(writer, config.Newlines.AfterChangelogVersion)
-			if err != nil {
-				return err
-			}
+	return filtered, nil
+}
+
+// filterByDate returns versions released between the since and until dates, inclusive.
+func (d *Diff) filterByDate(config *core.Config, vers []*semver.Version) ([]*semver.Version, error) {
+	if d.Since == "" && d.Until == "" {
+		return vers, nil
+	}
+
+	var since, until time.Time
+
+	if d.Since != "" {
+		var err error
+
+		since, err = time.Parse(time.DateOnly, d.Since)
+		if err != nil {
+			return nil, fmt.Errorf("since: %w", err)
 		}
+	}
+
+	if d.Until != "" {
+		var err error
+
+		until, err = time.Parse(time.DateOnly, d.Until)
+		if err != nil {
+			return nil, fmt.Errorf("until: %w", err)
+		}
+
+		// include the entire until day
+		until = until.AddDate(0, 0, 1)
+	}
 
-		firstOutput = false
+	filtered := make([]*semver.Version, 0, len(vers))
 
-		versionPath := filepath.Join(
-			config.ChangesDir,
-			d.Project,
-			ver.Original()+"."+config.VersionExt,
-		)
+	for _, ver := range vers {
+		versionPath, err := core.VersionFilePath(config, d.Project, ver)
+		if err != nil {
+			return nil, err
+		}
 
-		err = core.AppendFile(writer, versionPath)
+		released, err := core.VersionTime(config, versionPath)
 		if err != nil {
-			return err
+			return nil, err
 		}
+
+		if released.Before(since) || (!until.IsZero() && !released.Before(until)) {
+			continue
+		}
+
+		filtered = append(filtered, ver)
 	}
 
-	return err
+	return filtered, nil
 }
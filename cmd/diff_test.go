@@ -3,6 +3,7 @@ package cmd
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/miniscruff/changie/core"
 	"github.com/miniscruff/changie/then"
@@ -71,3 +72,141 @@ func TestDiff(t *testing.T) {
 		then.Equals(t, expected, builder.String())
 	})
 }
+
+func TestDiffByReleaseDate(t *testing.T) {
+	cfg := diffConfig()
+	cfg.VersionFrontmatter = true
+	then.WithTempDirConfig(t, cfg)
+
+	for _, release := range []struct {
+		version string
+		date    time.Time
+	}{
+		{"v0.1.0", time.Date(2025, 12, 20, 10, 0, 0, 0, time.UTC)},
+		{"v0.2.0", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"v0.3.0", time.Date(2026, 1, 15, 23, 0, 0, 0, time.UTC)},
+		{"v0.4.0", time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)},
+	} {
+		var builder strings.Builder
+
+		_, err := core.VersionMetadata{Time: release.date, Changes: 1}.WriteTo(&builder)
+		then.Nil(t, err)
+
+		builder.WriteString(release.version + "\n")
+		then.WriteFile(t, []byte(builder.String()), cfg.ChangesDir, release.version+".md")
+	}
+
+	t.Run("since", func(t *testing.T) {
+		cmd := NewDiff()
+		cmd.Since = "2026-01-01"
+
+		builder := strings.Builder{}
+		cmd.SetOut(&builder)
+
+		err := cmd.Run(cmd.Command, nil)
+		then.Nil(t, err)
+		then.Equals(t, "v0.4.0\nv0.3.0\nv0.2.0\n", builder.String())
+	})
+
+	t.Run("since and until with N", func(t *testing.T) {
+		cmd := NewDiff()
+		cmd.Since = "2025-12-01"
+		cmd.Until = "2026-01-15"
+
+		builder := strings.Builder{}
+		cmd.SetOut(&builder)
+
+		err := cmd.Run(cmd.Command, []string{"2"})
+		then.Nil(t, err)
+		then.Equals(t, "v0.3.0\nv0.2.0\n", builder.String())
+	})
+
+	t.Run("ascending", func(t *testing.T) {
+		cmd := NewDiff()
+		cmd.Order = core.VersionOrderAscending
+
+		builder := strings.Builder{}
+		cmd.SetOut(&builder)
+
+		err := cmd.Run(cmd.Command, []string{"2"})
+		then.Nil(t, err)
+		then.Equals(t, "v0.3.0\nv0.4.0\n", builder.String())
+	})
+
+	t.Run("bad date", func(t *testing.T) {
+		cmd := NewDiff()
+		cmd.Since = "yesterday"
+
+		err := cmd.Run(cmd.Command, nil)
+		then.NotNil(t, err)
+	})
+}
+
+func TestDiffByDateOrderWithMaintenanceRelease(t *testing.T) {
+	cfg := diffConfig()
+	cfg.VersionFrontmatter = true
+	then.WithTempDirConfig(t, cfg)
+
+	for _, release := range []struct {
+		version string
+		date    time.Time
+	}{
+		{"v1.0.0", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"v2.0.0", time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)},
+		{"v2.1.0", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{"v1.0.1", time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)},
+	} {
+		var builder strings.Builder
+
+		_, err := core.VersionMetadata{Time: release.date, Changes: 1}.WriteTo(&builder)
+		then.Nil(t, err)
+
+		builder.WriteString(release.version + "\n")
+		then.WriteFile(t, []byte(builder.String()), cfg.ChangesDir, release.version+".md")
+	}
+
+	t.Run("date descending", func(t *testing.T) {
+		cmd := NewDiff()
+		cmd.Order = core.VersionOrderDateDescending
+
+		builder := strings.Builder{}
+		cmd.SetOut(&builder)
+
+		err := cmd.Run(cmd.Command, []string{"2"})
+		then.Nil(t, err)
+		then.Equals(t, "v1.0.1\nv2.1.0\n", builder.String())
+	})
+
+	t.Run("date ascending", func(t *testing.T) {
+		cmd := NewDiff()
+		cmd.Order = core.VersionOrderDateAscending
+
+		builder := strings.Builder{}
+		cmd.SetOut(&builder)
+
+		err := cmd.Run(cmd.Command, []string{"2"})
+		then.Nil(t, err)
+		then.Equals(t, "v2.1.0\nv1.0.1\n", builder.String())
+	})
+
+	t.Run("configured date order", func(t *testing.T) {
+		cfg.VersionOrder = core.VersionOrderDateDescending
+		then.Nil(t, cfg.Save())
+
+		cmd := NewDiff()
+
+		builder := strings.Builder{}
+		cmd.SetOut(&builder)
+
+		err := cmd.Run(cmd.Command, []string{"2"})
+		then.Nil(t, err)
+		then.Equals(t, "v1.0.1\nv2.1.0\n", builder.String())
+	})
+}
+
+func TestErrorDiffWithoutRange(t *testing.T) {
+	cmd := NewDiff()
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Err(t, errDiffRangeRequired, err)
+}
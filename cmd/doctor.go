@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miniscruff/changie/core"
+)
+
+var errDoctorFoundProblems = errors.New("doctor found problems")
+
+type Doctor struct {
+	*cobra.Command
+}
+
+func NewDoctor() *Doctor {
+	d := &Doctor{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check released versions for problems",
+		Long: `Doctor compares git tags with version files and reports any tags without a
+version file and any version files without a tag.
+
+When using projects, every project is checked using tags prefixed with the project key
+and projects version separator.
+Exits with an error if any problems are found.`,
+		Args: cobra.NoArgs,
+		RunE: d.Run,
+	}
+
+	d.Command = cmd
+
+	return d
+}
+
+func (d *Doctor) Run(cmd *cobra.Command, args []string) error {
+	config, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	projectKeys := []string{""}
+	if len(config.Projects) > 0 {
+		projectKeys = projectKeys[:0]
+
+		for _, pc := range config.Projects {
+			projectKeys = append(projectKeys, pc.Key)
+		}
+	}
+
+	problems := 0
+
+	for _, key := range projectKeys {
+		var found int
+
+		found, err = d.checkVersions(cmd.OutOrStdout(), config, key)
+		if err != nil {
+			return err
+		}
+
+		problems += found
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%w: %d", errDoctorFoundProblems, problems)
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), "No problems found")
+
+	return err
+}
+
+// checkVersions writes every tag without a version file and every version file without a tag,
+// returning the number of problems found.
+func (d *Doctor) checkVersions(writer io.Writer, config *core.Config, projectKey string) (int, error) {
+	prefix := ""
+
+	if projectKey != "" {
+		var err error
+
+		config, err = config.ForProject(projectKey)
+		if err != nil {
+			return 0, err
+		}
+
+		prefix = projectKey + config.ProjectsVersionSeparator
+	}
+
+	fileVersions, err := core.GetFileVersions(config, false, projectKey)
+	if err != nil {
+		return 0, err
+	}
+
+	tagVersions, err := core.GetTagVersions(config, false, projectKey)
+	if err != nil {
+		return 0, err
+	}
+
+	files := make(map[string]bool, len(fileVersions))
+	for _, v := range fileVersions {
+		files[v.String()] = true
+	}
+
+	tags := make(map[string]bool, len(tagVersions))
+	for _, v := range tagVersions {
+		tags[v.String()] = true
+	}
+
+	problems := 0
+
+	for _, v := range tagVersions {
+		if files[v.String()] {
+			continue
+		}
+
+		problems++
+
+		_, err = fmt.Fprintf(writer, "Tag %s%s has no version file\n", prefix, v.Original())
+		if err != nil {
+			return problems, err
+		}
+	}
+
+	for _, v := range fileVersions {
+		if tags[v.String()] {
+			continue
+		}
+
+		problems++
+
+		_, err = fmt.Fprintf(writer, "Version file for %s%s has no tag\n", prefix, v.Original())
+		if err != nil {
+			return problems, err
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/miniscruff/changie/core"
+	"github.com/miniscruff/changie/then"
+)
+
+func doctorTestConfig() *core.Config {
+	return &core.Config{
+		ChangesDir:               "chgs",
+		UnreleasedDir:            "unrel",
+		VersionExt:               "md",
+		ProjectsVersionSeparator: "/",
+	}
+}
+
+func gitTags(t *testing.T, tags ...string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	args := [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=a", "-c", "user.email=a@b.c", "commit", "-q", "--allow-empty", "-m", "init"},
+	}
+	for _, tag := range tags {
+		args = append(args, []string{"tag", tag})
+	}
+
+	for _, arg := range args {
+		err := exec.Command("git", arg...).Run()
+		then.Nil(t, err)
+	}
+}
+
+func TestDoctorFindsNoProblems(t *testing.T) {
+	cfg := doctorTestConfig()
+	then.WithTempDirConfig(t, cfg)
+	gitTags(t, "v0.1.0", "v0.2.0")
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.1.0.md")
+	then.CreateFile(t, cfg.ChangesDir, "v0.2.0.md")
+
+	builder := strings.Builder{}
+	doctor := NewDoctor()
+	doctor.SetOut(&builder)
+
+	err := doctor.Run(doctor.Command, nil)
+	then.Nil(t, err)
+	then.Equals(t, "No problems found\n", builder.String())
+}
+
+func TestDoctorReportsMissingTagsAndFiles(t *testing.T) {
+	cfg := doctorTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "Api", Key: "api"},
+		{Label: "Web", Key: "web"},
+	}
+	then.WithTempDirConfig(t, cfg)
+	gitTags(t, "api/v0.1.0", "api/v0.2.0", "web/v1.0.0")
+
+	then.CreateFile(t, cfg.ChangesDir, "api", "v0.1.0.md")
+	then.CreateFile(t, cfg.ChangesDir, "web", "v1.0.0.md")
+	then.CreateFile(t, cfg.ChangesDir, "web", "v1.1.0.md")
+
+	builder := strings.Builder{}
+	doctor := NewDoctor()
+	doctor.SetOut(&builder)
+
+	err := doctor.Run(doctor.Command, nil)
+	then.Err(t, errDoctorFoundProblems, err)
+	then.Equals(t, `Tag api/v0.2.0 has no version file
+Version file for web/v1.1.0 has no tag
+`, builder.String())
+}
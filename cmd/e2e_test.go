@@ -47,6 +47,7 @@ func testNew(t *testing.T, cmd *cobra.Command, w io.Writer, body string) {
 		[]byte{13},
 		[]byte(body),
 		[]byte{13},
+		[]byte{13}, // confirm review
 	)
 	then.Nil(t, cmd.Execute())
 }
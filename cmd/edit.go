@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miniscruff/changie/core"
+)
+
+var (
+	errNoChangesToEdit       = errors.New("no unreleased changes found to edit")
+	errMultipleChangesToEdit = errors.New("multiple changes match and prompt is disabled")
+)
+
+type Edit struct {
+	*cobra.Command
+
+	// cli args
+	Projects    []string
+	Component   string
+	Kind        string
+	Body        string
+	BodyEditor  bool
+	Custom      []string
+	Interactive bool
+
+	// dependencies
+	TemplateCache *core.TemplateCache
+}
+
+func NewEdit(templateCache *core.TemplateCache) *Edit {
+	e := &Edit{
+		TemplateCache: templateCache,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "edit [filter]",
+		Short: "Edit an existing unreleased change file",
+		Long: `Edits an existing unreleased change file.
+
+Unreleased changes can be filtered by a value found in the file name or body.
+If more than one change matches you will be prompted to select one.
+
+The same prompts as the new command are asked again, pre-filled with
+the existing values, and the change is validated before being saved.
+The file is rewritten in place unless the project, component or kind changed,
+in which case it is renamed to match the fragment file format.
+
+Prompts are disabled if the CI env var is true or with --interactive=false,
+in which case existing values are kept unless replaced by a flag.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: e.Run,
+	}
+
+	cmd.Flags().StringSliceVarP(
+		&e.Projects,
+		"projects", "j",
+		[]string{},
+		"Set the change projects without a prompt",
+	)
+	cmd.Flags().StringVarP(
+		&e.Component,
+		"component", "c",
+		"",
+		"Set the change component without a prompt",
+	)
+	cmd.Flags().StringVarP(
+		&e.Kind,
+		"kind", "k",
+		"",
+		"Set the change kind without a prompt",
+	)
+	cmd.Flags().StringVarP(
+		&e.Body,
+		"body", "b",
+		"",
+		"Set the change body without a prompt",
+	)
+	cmd.Flags().BoolVarP(
+		&e.BodyEditor,
+		"editor", "e",
+		false,
+		"Edit body message using your text editor defined by 'EDITOR' env variable",
+	)
+	cmd.Flags().StringSliceVarP(
+		&e.Custom,
+		"custom", "m",
+		nil,
+		"Set custom values without a prompt",
+	)
+	cmd.Flags().BoolVarP(
+		&e.Interactive,
+		"interactive",
+		"i",
+		true,
+		"Set values with prompts",
+	)
+
+	e.Command = cmd
+
+	return e
+}
+
+func (e *Edit) Run(cmd *cobra.Command, args []string) error {
+	config, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	filter := ""
+	if len(args) > 0 {
+		filter = args[0]
+	}
+
+	enabled := e.Interactive && strings.ToLower(os.Getenv("CI")) != "true"
+
+	change, err := e.selectChange(config, filter, enabled)
+	if err != nil {
+		return err
+	}
+
+	customValues, err := core.CustomMapFromStrings(e.Custom)
+	if err != nil {
+		return err
+	}
+
+	prompts := &core.Prompts{
+		StdinReader:      e.InOrStdin(),
+		BodyEditor:       e.BodyEditor,
+		Projects:         e.Projects,
+		Component:        e.Component,
+		Kind:             e.Kind,
+		Body:             e.Body,
+		TimeNow:          func() time.Time { return change.Time },
+		Config:           config,
+		Customs:          customValues,
+		EditorCmdBuilder: core.BuildCommand,
+		Enabled:          enabled,
+		Defaults:         &change,
+	}
+
+	// Without prompts, any value not replaced by a flag is kept as is.
+	if !enabled {
+		e.fillFromChange(prompts, config, change)
+	}
+
+	changes, err := prompts.BuildChanges()
+	if err != nil {
+		return err
+	}
+
+	return e.saveChanges(config, change, changes)
+}
+
+// selectChange finds the unreleased change to edit, prompting if more than one matches.
+func (e *Edit) selectChange(config *core.Config, filter string, enabled bool) (core.Change, error) {
+	changeFiles, err := core.FindChangeFiles(config, nil)
+	if err != nil {
+		return core.Change{}, err
+	}
+
+	var (
+		changes  []core.Change
+		loadErrs []error
+		labels   []string
+	)
+
+	for _, cf := range changeFiles {
+		nameMatches := strings.Contains(filepath.Base(cf), filter)
+
+		// fragments that fail to load can only be matched by name,
+		// and only fail if they are the fragment being edited
+		change, loadErr := core.LoadChange(cf)
+		if loadErr != nil && !nameMatches {
+			continue
+		}
+
+		if !nameMatches && !strings.Contains(change.Body, filter) {
+			continue
+		}
+
+		body, _, _ := strings.Cut(change.Body, "\n")
+		changes = append(changes, change)
+		loadErrs = append(loadErrs, loadErr)
+		labels = append(labels, fmt.Sprintf("%s: %s", filepath.Base(cf), body))
+	}
+
+	selected := 0
+
+	switch {
+	case len(changes) == 0:
+		return core.Change{}, errNoChangesToEdit
+	case len(changes) > 1 && !enabled:
+		return core.Change{}, fmt.Errorf("%w: %s", errMultipleChangesToEdit, strings.Join(labels, ", "))
+	case len(changes) > 1:
+		label, err := core.Custom{
+			Type:        core.CustomEnum,
+			Label:       "Change",
+			EnumOptions: labels,
+		}.AskPrompt(e.InOrStdin())
+		if err != nil {
+			return core.Change{}, err
+		}
+
+		selected = slices.Index(labels, label)
+		if selected < 0 {
+			return core.Change{}, errNoChangesToEdit
+		}
+	}
+
+	if loadErrs[selected] != nil {
+		return core.Change{}, loadErrs[selected]
+	}
+
+	return changes[selected], nil
+}
+
+// fillFromChange sets any values not provided as flags from the existing change.
+func (e *Edit) fillFromChange(prompts *core.Prompts, config *core.Config, change core.Change) {
+	if len(prompts.Projects) == 0 && change.Project != "" {
+		prompts.Projects = []string{change.Project}
+	}
+
+	if len(prompts.Projects) == 0 && len(change.Projects) > 0 {
+		prompts.Projects = change.Projects
+	}
+
+	if prompts.Component == "" {
+		prompts.Component = change.Component
+	}
+
+	if prompts.Kind == "" {
+		prompts.Kind = change.Kind
+	}
+
+	if prompts.Body == "" {
+		prompts.Body = change.Body
+	}
+
+	// projects can override the kinds, components and custom choices
+	if len(prompts.Projects) > 0 {
+		if projectConfig, err := config.ForProject(prompts.Projects[0]); err == nil {
+			config = projectConfig
+		}
+	}
+
+	var choices []core.Custom
+
+	kc := config.KindFromKeyOrLabel(prompts.Kind)
+	if kc == nil || !kc.SkipGlobalChoices {
+		choices = append(choices, config.CustomChoices...)
+	}
+
+	if kc != nil {
+		choices = append(choices, kc.AdditionalChoices...)
+	}
+
+	if cc := config.ComponentFromKeyOrLabel(prompts.Component); cc != nil {
+		choices = append(choices, cc.AdditionalChoices...)
+	}
+
+	for _, custom := range choices {
+		if _, found := prompts.Customs[custom.Key]; !found && change.Custom[custom.Key] != "" {
+			prompts.Customs[custom.Key] = change.Custom[custom.Key]
+		}
+	}
+}
+
+// saveChanges writes our edited changes, keeping the original file name unless
+// the project, component or kind changed.
+// The original file is removed if it is no longer used.
+func (e *Edit) saveChanges(config *core.Config, original core.Change, changes []*core.Change) error {
+	keepOriginal := false
+
+	for _, change := range changes {
+		path := original.Filename
+
+		if change.Project != original.Project ||
+			change.Component != original.Component ||
+			change.Kind != original.Kind {
+			var err error
+
+			path, err = config.FragmentPath(e.TemplateCache, change)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Rewriting our own file is not a conflict.
+		if path == original.Filename {
+			keepOriginal = true
+
+			err := writeChangeTo(change, path)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		path, err := core.UniquePath(path, nil)
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(path), core.CreateDirMode)
+		if err != nil {
+			return err
+		}
+
+		err = createChangeFile(change, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if keepOriginal {
+		return nil
+	}
+
+	return os.Remove(original.Filename)
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/miniscruff/changie/core"
+	"github.com/miniscruff/changie/then"
+)
+
+func TestEditRewritesChangeInPlace(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	first := &core.Change{Kind: "added", Body: "first typo", Time: newMockTime()}
+	second := &core.Change{Kind: "removed", Body: "second", Time: newMockTime()}
+	writeChangeFile(t, cfg, first)
+	writeChangeFile(t, cfg, second)
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.Interactive = false
+	cmd.Body = "first fixed"
+
+	err := cmd.Run(cmd.Command, []string{"typo"})
+	then.Nil(t, err)
+
+	changeContent := fmt.Sprintf(
+		"kind: added\nbody: first fixed\ntime: %s\n",
+		newMockTime().Format(time.RFC3339Nano),
+	)
+
+	then.FileContents(t, changeContent, first.Filename)
+	then.DirectoryFileCount(t, 2, cfg.ChangesDir, cfg.UnreleasedDir)
+}
+
+func TestEditRenamesChangeWhenKindChanges(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	change := &core.Change{Kind: "added", Body: "some body", Time: newMockTime()}
+	writeChangeFile(t, cfg, change)
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.Interactive = false
+	cmd.Kind = "other"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContent := fmt.Sprintf(
+		"kind: other\nbody: some body\ntime: %s\n",
+		newMockTime().Format(time.RFC3339Nano),
+	)
+
+	then.FileNotExists(t, change.Filename)
+	then.FileContents(
+		t,
+		changeContent,
+		cfg.ChangesDir, cfg.UnreleasedDir, "other-20210522-133010.yaml",
+	)
+}
+
+func TestEditWithPromptsSelectsChange(t *testing.T) {
+	// we need to override this value as it would fail in CI with the interactive system
+	// but is ok here as we override stdin and stdout anyway
+	t.Setenv("CI", "false")
+
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+	reader, writer := then.WithReadWritePipe(t)
+
+	first := &core.Change{Kind: "added", Body: "first", Time: newMockTime()}
+	second := &core.Change{Kind: "removed", Body: "second", Time: newMockTime()}
+	writeChangeFile(t, cfg, first)
+	writeChangeFile(t, cfg, second)
+
+	then.DelayWrite(
+		t, writer,
+		// select the second change
+		[]byte{106, 13},
+		// keep the existing kind
+		[]byte{13},
+		[]byte("second edited"),
+		[]byte{13},
+	)
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.SetIn(reader)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContent := fmt.Sprintf(
+		"kind: removed\nbody: second edited\ntime: %s\n",
+		newMockTime().Format(time.RFC3339Nano),
+	)
+
+	then.FileContents(t, changeContent, second.Filename)
+}
+
+func TestEditSkipsMalformedChangesNotMatched(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	first := &core.Change{Kind: "added", Body: "first typo", Time: newMockTime()}
+	writeChangeFile(t, cfg, first)
+	then.WriteFile(t, []byte("kind: [broken"), cfg.ChangesDir, cfg.UnreleasedDir, "broken.yaml")
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.Interactive = false
+	cmd.Body = "first fixed"
+
+	err := cmd.Run(cmd.Command, []string{"typo"})
+	then.Nil(t, err)
+
+	err = cmd.Run(cmd.Command, []string{"broken"})
+	then.NotNil(t, err)
+}
+
+func TestErrorEditNoChanges(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "first"})
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.Interactive = false
+
+	err := cmd.Run(cmd.Command, []string{"missing"})
+	then.Err(t, errNoChangesToEdit, err)
+}
+
+func TestErrorEditMultipleChangesWithoutPrompts(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "first"})
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "second"})
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.Interactive = false
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Err(t, errMultipleChangesToEdit, err)
+}
+
+func TestErrorEditInvalidKind(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	change := &core.Change{Kind: "added", Body: "first"}
+	writeChangeFile(t, cfg, change)
+
+	cmd := NewEdit(core.NewTemplateCache())
+	cmd.Interactive = false
+	cmd.Kind = "missing"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.NotNil(t, err)
+	then.FileExists(t, change.Filename)
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miniscruff/changie/core"
+)
+
+// unreleasedVersion is the version shown for matches in unreleased fragments.
+const unreleasedVersion = "unreleased"
+
+// FindResult is a single line matching a find query.
+type FindResult struct {
+	// Version the change was released in, or unreleased
+	Version string `json:"version"`
+	// Project key of the change, if using projects
+	Project string `json:"project,omitempty"`
+	// Kind of the change, empty if matched in a version file without fragments
+	Kind string `json:"kind,omitempty"`
+	// Component of the change, empty if matched in a version file without fragments
+	Component string `json:"component,omitempty"`
+	// Line matching the query
+	Line string `json:"line"`
+	// File the match was found in
+	File string `json:"file"`
+}
+
+type Find struct {
+	*cobra.Command
+
+	// cli args
+	IncludeDirs []string
+	Project     string
+	Component   string
+	Kind        string
+	Custom      []string
+	JSON        bool
+}
+
+func NewFind() *Find {
+	f := &Find{}
+
+	cmd := &cobra.Command{
+		Use:   "find query",
+		Short: "Search unreleased and released changes",
+		Long: `Find searches the bodies and custom values of changes for the query, ignoring case.
+
+Unreleased fragments are searched first, followed by every released version, newest first.
+Released versions are searched using the fragments stored in the version frontmatter,
+see versionFrontmatter, otherwise each line of the version file is searched.
+
+Results can be filtered by project, component, kind and custom values.
+Custom values are filtered using the same "Key=Value" format as the new command.
+Version files without fragments are skipped when filtering by component, kind or custom values.`,
+		Example: `changie find "memory leak" --custom Issue=1234`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    f.Run,
+	}
+
+	cmd.Flags().StringSliceVarP(
+		&f.IncludeDirs,
+		"include", "i",
+		nil,
+		"Include extra directories to search for change files, relative to change directory",
+	)
+	cmd.Flags().StringVarP(
+		&f.Project,
+		"project", "j",
+		"",
+		"Only search changes for this project",
+	)
+	cmd.Flags().StringVarP(
+		&f.Component,
+		"component", "c",
+		"",
+		"Only search changes for this component",
+	)
+	cmd.Flags().StringVarP(
+		&f.Kind,
+		"kind", "k",
+		"",
+		"Only search changes of this kind",
+	)
+	cmd.Flags().StringSliceVar(
+		&f.Custom,
+		"custom",
+		nil,
+		"Only search changes with this custom value, using the Key=Value format",
+	)
+	cmd.Flags().BoolVar(
+		&f.JSON,
+		"json",
+		false,
+		"Echo the results as JSON",
+	)
+
+	f.Command = cmd
+
+	return f
+}
+
+func (f *Find) Run(cmd *cobra.Command, args []string) error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	customs, err := core.CustomMapFromStrings(f.Custom)
+	if err != nil {
+		return err
+	}
+
+	projectKeys := []string{""}
+
+	if f.Project != "" {
+		pc, err := cfg.Project(f.Project)
+		if err != nil {
+			return err
+		}
+
+		projectKeys = []string{pc.Key}
+	} else if len(cfg.Projects) > 0 {
+		projectKeys = projectKeys[:0]
+
+		for _, pc := range cfg.Projects {
+			projectKeys = append(projectKeys, pc.Key)
+		}
+	}
+
+	query := strings.ToLower(args[0])
+	results := make([]FindResult, 0)
+
+	for _, key := range projectKeys {
+		projectCfg := cfg
+
+		if key != "" {
+			projectCfg, err = cfg.ForProject(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		var found []FindResult
+
+		found, err = f.findInProject(projectCfg, key, query, customs)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, found...)
+	}
+
+	if f.JSON {
+		bs, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = cmd.OutOrStdout().Write(append(bs, '\n'))
+
+		return err
+	}
+
+	return writeFindResults(cmd.OutOrStdout(), results)
+}
+
+// findInProject searches the unreleased fragments and released versions of a project.
+func (f *Find) findInProject(
+	cfg *core.Config,
+	projectKey, query string,
+	customs map[string]string,
+) ([]FindResult, error) {
+	var results []FindResult
+
+	changes, err := core.GetChanges(cfg, f.IncludeDirs, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if matchesChangeFilters(cfg, change, f.Component, f.Kind, customs) {
+			results = append(results, changeResults(change, unreleasedVersion, projectKey, change.Filename, query)...)
+		}
+	}
+
+	vers, err := core.GetFileVersions(cfg, false, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := f.Component != "" || f.Kind != "" || len(customs) > 0
+
+	for _, ver := range vers {
+		versionPath, err := core.VersionFilePath(cfg, projectKey, ver)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata, notes, err := core.ReadVersionFile(cfg, versionPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata != nil && len(metadata.Fragments) > 0 {
+			for _, change := range metadata.Fragments {
+				if matchesChangeFilters(cfg, change, f.Component, f.Kind, customs) {
+					results = append(results, changeResults(change, ver.Original(), projectKey, versionPath, query)...)
+				}
+			}
+
+			continue
+		}
+
+		if filtered {
+			continue
+		}
+
+		for _, line := range strings.Split(string(notes), "\n") {
+			if strings.Contains(strings.ToLower(line), query) {
+				results = append(results, FindResult{
+					Version: ver.Original(),
+					Project: projectKey,
+					Line:    strings.TrimSpace(line),
+					File:    versionPath,
+				})
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// changeResults returns a result for every body line and custom value of a change matching the query.
+func changeResults(change core.Change, version, projectKey, file, query string) []FindResult {
+	var lines []string
+
+	for _, line := range strings.Split(change.Body, "\n") {
+		if strings.Contains(strings.ToLower(line), query) {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+
+	customKeys := make([]string, 0, len(change.Custom))
+	for key := range change.Custom {
+		customKeys = append(customKeys, key)
+	}
+
+	slices.Sort(customKeys)
+
+	for _, key := range customKeys {
+		if strings.Contains(strings.ToLower(change.Custom[key]), query) {
+			lines = append(lines, key+": "+change.Custom[key])
+		}
+	}
+
+	results := make([]FindResult, 0, len(lines))
+
+	for _, line := range lines {
+		results = append(results, FindResult{
+			Version:   version,
+			Project:   projectKey,
+			Kind:      change.Kind,
+			Component: change.Component,
+			Line:      line,
+			File:      file,
+		})
+	}
+
+	return results
+}
+
+// writeFindResults writes the results as a table of version, project, kind and line.
+func writeFindResults(writer io.Writer, results []FindResult) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(table, "VERSION\tPROJECT\tKIND\tLINE")
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		_, err = fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", result.Version, result.Project, result.Kind, result.Line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miniscruff/changie/core"
+	"github.com/miniscruff/changie/then"
+)
+
+func findTestConfig(t *testing.T) *core.Config {
+	cfg := batchTestConfig()
+	cfg.Components = []core.ComponentConfig{{Label: "api"}, {Label: "cli"}}
+	cfg.VersionFrontmatter = true
+	then.WithTempDirConfig(t, cfg)
+
+	var builder strings.Builder
+
+	_, err := core.VersionMetadata{
+		Time:    time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC),
+		Changes: 2,
+		Fragments: []core.Change{
+			{Kind: "added", Component: "api", Body: "Fix memory leak in parser"},
+			{Kind: "removed", Component: "cli", Body: "Drop flag", Custom: map[string]string{"Issue": "1234"}},
+		},
+	}.WriteTo(&builder)
+	then.Nil(t, err)
+
+	builder.WriteString("## v0.2.0\n### added\n* Fix memory leak in parser\n")
+	then.WriteFile(t, []byte(builder.String()), cfg.ChangesDir, "v0.2.0.md")
+	then.WriteFile(t, []byte("## v0.1.0\n### added\n* First memory pool\n"), cfg.ChangesDir, "v0.1.0.md")
+
+	writeChangeFile(t, cfg, &core.Change{
+		Kind:      "added",
+		Component: "cli",
+		Body:      "New memory flag\nwith details",
+		Custom:    map[string]string{"Issue": "99"},
+	})
+
+	return cfg
+}
+
+func TestFindSearchesReleaseHistory(t *testing.T) {
+	findTestConfig(t)
+
+	builder := strings.Builder{}
+	find := NewFind()
+	find.SetOut(&builder)
+
+	err := find.Run(find.Command, []string{"MEMORY"})
+	then.Nil(t, err)
+	then.Equals(t, `VERSION     PROJECT  KIND   LINE
+unreleased           added  New memory flag
+v0.2.0               added  Fix memory leak in parser
+v0.1.0                      * First memory pool
+`, builder.String())
+}
+
+func TestFindFiltersAsJSON(t *testing.T) {
+	cfg := findTestConfig(t)
+
+	builder := strings.Builder{}
+	find := NewFind()
+	find.SetOut(&builder)
+	find.Custom = []string{"Issue=1234"}
+	find.JSON = true
+
+	err := find.Run(find.Command, []string{"1234"})
+	then.Nil(t, err)
+
+	var results []FindResult
+
+	then.Nil(t, json.Unmarshal([]byte(builder.String()), &results))
+	then.SliceEquals(t, []FindResult{
+		{
+			Version:   "v0.2.0",
+			Kind:      "removed",
+			Component: "cli",
+			Line:      "Issue: 1234",
+			File:      filepath.Join(cfg.ChangesDir, "v0.2.0.md"),
+		},
+	}, results)
+}
+
+func TestErrorFindBadCustom(t *testing.T) {
+	findTestConfig(t)
+
+	find := NewFind()
+	find.Custom = []string{"Issue"}
+
+	err := find.Run(find.Command, []string{"1234"})
+	then.NotNil(t, err)
+}
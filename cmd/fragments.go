@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miniscruff/changie/core"
+)
+
+var (
+	errFragmentNotFound      = errors.New("fragment not found")
+	errProjectsNotConfigured = errors.New("projects are not configured")
+)
+
+type Fragments struct {
+	*cobra.Command
+
+	// cli args
+	IncludeDirs []string
+	Project     string
+	Component   string
+	Kind        string
+	Custom      []string
+	MoveProject string
+
+	// dependencies
+	TemplateCache *core.TemplateCache
+}
+
+func NewFragments(templateCache *core.TemplateCache) *Fragments {
+	f := &Fragments{
+		TemplateCache: templateCache,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "fragments",
+		Short: "Manage unreleased change fragments",
+		Long: `Manage unreleased change fragments.
+
+Fragments are found and sorted the same way as when batching a release,
+so the listed order matches the order changes are written in.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.PersistentFlags().StringSliceVarP(
+		&f.IncludeDirs,
+		"include", "i",
+		nil,
+		"Include extra directories to search for change files, relative to change directory",
+	)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List unreleased change fragments",
+		Long: `List unreleased change fragments.
+
+Fragments can be filtered by project, component, kind and custom values.
+Custom values are filtered using the same "Key=Value" format as the new command.`,
+		Args: cobra.NoArgs,
+		RunE: f.List,
+	}
+	listCmd.Flags().StringVarP(
+		&f.Project,
+		"project", "j",
+		"",
+		"Only list fragments for this project",
+	)
+	listCmd.Flags().StringVarP(
+		&f.Component,
+		"component", "c",
+		"",
+		"Only list fragments for this component",
+	)
+	listCmd.Flags().StringVarP(
+		&f.Kind,
+		"kind", "k",
+		"",
+		"Only list fragments of this kind",
+	)
+	listCmd.Flags().StringSliceVarP(
+		&f.Custom,
+		"custom", "m",
+		nil,
+		"Only list fragments with these custom values",
+	)
+
+	showCmd := &cobra.Command{
+		Use:   "show fragment...",
+		Short: "Show the contents of change fragments",
+		Long: `Show the contents of change fragments.
+
+Fragments can be referenced by path, file name or file name without the extension.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: f.Show,
+	}
+
+	removeCmd := &cobra.Command{
+		Use:     "rm fragment...",
+		Aliases: []string{"remove"},
+		Short:   "Remove change fragments",
+		Long: `Remove change fragments.
+
+Fragments can be referenced by path, file name or file name without the extension.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: f.Remove,
+	}
+
+	moveCmd := &cobra.Command{
+		Use:   "move fragment... --project key",
+		Short: "Move change fragments to another project",
+		Long: `Move change fragments to another project.
+
+The fragment project is updated and the file is renamed to match the fragment file format.
+Fragments can be referenced by path, file name or file name without the extension.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: f.Move,
+	}
+	moveCmd.Flags().StringVarP(
+		&f.MoveProject,
+		"project", "j",
+		"",
+		"Project label or key to move the fragments to",
+	)
+	_ = moveCmd.MarkFlagRequired("project")
+
+	cmd.AddCommand(listCmd, showCmd, removeCmd, moveCmd)
+
+	f.Command = cmd
+
+	return f
+}
+
+func (f *Fragments) List(cmd *cobra.Command, args []string) error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	customs, err := core.CustomMapFromStrings(f.Custom)
+	if err != nil {
+		return err
+	}
+
+	project := f.Project
+	if project != "" {
+		pc, err := cfg.Project(project)
+		if err != nil {
+			return err
+		}
+
+		project = pc.Key
+	}
+
+	err = checkUnknownFiles(cmd, cfg, f.IncludeDirs)
+	if err != nil {
+		return err
+	}
+
+	changes, err := core.GetChanges(cfg, f.IncludeDirs, project)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+
+	for _, change := range changes {
+		if !f.matchesFilters(cfg, change, customs) {
+			continue
+		}
+
+		body, _, _ := strings.Cut(change.Body, "\n")
+
+		_, err = fmt.Fprintf(
+			writer,
+			"%s\t%s\t%s\t%s\t%s\n",
+			change.Filename,
+			fragmentProjects(change),
+			change.Component,
+			change.KindLabel,
+			body,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// fragmentProjects returns the project of a change, or its shared projects, for display.
+func fragmentProjects(change core.Change) string {
+	if len(change.Projects) > 0 {
+		return strings.Join(change.Projects, ",")
+	}
+
+	return change.Project
+}
+
+func (f *Fragments) matchesFilters(cfg *core.Config, change core.Change, customs map[string]string) bool {
+	return matchesChangeFilters(cfg, change, f.Component, f.Kind, customs)
+}
+
+// matchesChangeFilters returns whether a change has the component, kind and custom values,
+// where empty filters match every change.
+func matchesChangeFilters(
+	cfg *core.Config,
+	change core.Change,
+	component, kind string,
+	customs map[string]string,
+) bool {
+	if component != "" && change.Component != component {
+		return false
+	}
+
+	if kind != "" {
+		// projects can override kinds
+		kc := cfg.ForChange(change).KindFromKeyOrLabel(change.Kind)
+		if kc == nil || (kc.Key != kind && kc.Label != kind) {
+			return false
+		}
+	}
+
+	for key, value := range customs {
+		if change.Custom[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *Fragments) Show(cmd *cobra.Command, args []string) error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	paths, err := f.findFragmentFiles(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	writer := cmd.OutOrStdout()
+
+	for i, path := range paths {
+		if i > 0 {
+			_ = core.WriteNewlines(writer, 1)
+		}
+
+		_, err = fmt.Fprintf(writer, "# %s\n", path)
+		if err != nil {
+			return err
+		}
+
+		err = core.AppendFile(writer, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Fragments) Remove(cmd *cobra.Command, args []string) error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	paths, err := f.findFragmentFiles(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		err = os.Remove(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Fragments) Move(cmd *cobra.Command, args []string) error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Projects) == 0 {
+		return errProjectsNotConfigured
+	}
+
+	pc, err := cfg.Project(f.MoveProject)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, f.MoveProject)
+	}
+
+	// projects can override kinds and components, so validate against the target project
+	projectCfg, err := cfg.ForProject(pc.Key)
+	if err != nil {
+		return err
+	}
+
+	paths, err := f.findFragmentFiles(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]core.Change, 0, len(paths))
+
+	for _, path := range paths {
+		change, err := core.LoadChange(path)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, change)
+	}
+
+	// validate every fragment before moving any of them
+	for i := range changes {
+		changes[i].Project = pc.Key
+		changes[i].Projects = nil
+
+		core.ResolveAliases(projectCfg, &changes[i])
+
+		err = core.ValidateChange(projectCfg, changes[i])
+		if err != nil {
+			return fmt.Errorf("moving %s to %s: %w", changes[i].Filename, pc.Key, err)
+		}
+	}
+
+	for _, change := range changes {
+		oldPath := change.Filename
+
+		newPath, err := cfg.FragmentPath(f.TemplateCache, &change)
+		if err != nil {
+			return err
+		}
+
+		if newPath == oldPath {
+			err = writeChangeTo(&change, newPath)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		newPath, err = core.UniquePath(newPath, nil)
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(newPath), core.CreateDirMode)
+		if err != nil {
+			return err
+		}
+
+		err = createChangeFile(&change, newPath)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(oldPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// remove any subdirectories the moved fragments left empty
+	return core.RemoveEmptyDirs(filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir), false)
+}
+
+// findFragmentFiles returns the paths of unreleased fragments referenced by path, file name
+// or file name without the extension.
+// Fragments are matched by path only, so invalid fragments can still be shown or removed.
+func (f *Fragments) findFragmentFiles(cfg *core.Config, names []string) ([]string, error) {
+	changeFiles, err := core.FindChangeFiles(cfg, f.IncludeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]string, 0, len(names))
+
+	for _, name := range names {
+		index := slices.IndexFunc(changeFiles, func(path string) bool {
+			base := filepath.Base(path)
+
+			return filepath.Clean(name) == filepath.Clean(path) ||
+				name == base ||
+				name == strings.TrimSuffix(base, filepath.Ext(base))
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", errFragmentNotFound, name)
+		}
+
+		found = append(found, changeFiles[index])
+	}
+
+	return found, nil
+}
+
+// checkUnknownFiles warns about files in the unreleased directory that are not change fragments,
+// or fails if strict fragments are enabled.
+// Known paths, such as version header and footer files, are relative to the unreleased directory.
+func checkUnknownFiles(cmd *cobra.Command, cfg *core.Config, includeDirs []string, knownPaths ...string) error {
+	unknownFiles, err := core.UnknownUnreleasedFiles(cfg, includeDirs, knownPaths...)
+	if err != nil {
+		return err
+	}
+
+	if len(unknownFiles) > 0 && cfg.StrictFragments {
+		return fmt.Errorf("%w: %s", core.ErrUnknownFragmentFile, strings.Join(unknownFiles, ", "))
+	}
+
+	for _, path := range unknownFiles {
+		_, err = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v: %s\n", core.ErrUnknownFragmentFile, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
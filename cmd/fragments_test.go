@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/miniscruff/changie/core"
+	"github.com/miniscruff/changie/then"
+)
+
+func fragmentsTestConfig() *core.Config {
+	cfg := batchTestConfig()
+	cfg.FragmentFileFormat = "{{.Project}}-{{.Kind}}-{{.Body}}"
+	cfg.Components = []core.ComponentConfig{{Label: "api"}, {Label: "cli"}}
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+	}
+
+	return cfg
+}
+
+func TestFragmentsListFiltersChanges(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Project: "a", Component: "api", Kind: "added", Body: "first"})
+	writeChangeFile(t, cfg, &core.Change{
+		Project:   "a",
+		Component: "cli",
+		Kind:      "added",
+		Body:      "second",
+		Custom:    map[string]string{"Issue": "15"},
+	})
+	writeChangeFile(t, cfg, &core.Change{Project: "b", Component: "cli", Kind: "removed", Body: "third"})
+
+	for _, tc := range []struct {
+		name     string
+		setup    func(*Fragments)
+		expected []string
+	}{
+		{
+			name:     "all",
+			setup:    func(f *Fragments) {},
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "project",
+			setup:    func(f *Fragments) { f.Project = "A" },
+			expected: []string{"first", "second"},
+		},
+		{
+			name:     "component",
+			setup:    func(f *Fragments) { f.Component = "cli" },
+			expected: []string{"second", "third"},
+		},
+		{
+			name:     "kind",
+			setup:    func(f *Fragments) { f.Kind = "removed" },
+			expected: []string{"third"},
+		},
+		{
+			name:     "custom",
+			setup:    func(f *Fragments) { f.Custom = []string{"Issue=15"} },
+			expected: []string{"second"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var builder strings.Builder
+
+			f := NewFragments(core.NewTemplateCache())
+			f.SetOut(&builder)
+			tc.setup(f)
+
+			err := f.List(f.Command, nil)
+			then.Nil(t, err)
+
+			lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+			then.SliceLen(t, len(tc.expected), lines)
+
+			for i, body := range tc.expected {
+				then.True(t, strings.HasSuffix(lines[i], body))
+			}
+		})
+	}
+}
+
+func TestFragmentsListFiltersByProjectKind(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	cfg.Projects[1].Kinds = []core.KindConfig{{Label: "Command", Key: "command"}}
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Project: "a", Kind: "added", Body: "first"})
+	writeChangeFile(t, cfg, &core.Change{Project: "b", Kind: "command", Body: "second"})
+
+	var builder strings.Builder
+
+	f := NewFragments(core.NewTemplateCache())
+	f.SetOut(&builder)
+	f.Kind = "Command"
+
+	err := f.List(f.Command, nil)
+	then.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+	then.SliceLen(t, 1, lines)
+	then.True(t, strings.HasSuffix(lines[0], "second"))
+}
+
+func TestErrorFragmentsListBadProject(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	f := NewFragments(core.NewTemplateCache())
+	f.Project = "missing"
+
+	err := f.List(f.Command, nil)
+	then.NotNil(t, err)
+}
+
+func TestFragmentsShowChange(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	change := &core.Change{Project: "a", Kind: "added", Body: "first"}
+	writeChangeFile(t, cfg, change)
+
+	var builder strings.Builder
+
+	f := NewFragments(core.NewTemplateCache())
+	f.SetOut(&builder)
+
+	contents, err := os.ReadFile(change.Filename)
+	then.Nil(t, err)
+
+	name := strings.TrimSuffix(filepath.Base(change.Filename), ".yaml")
+	err = f.Show(f.Command, []string{name})
+	then.Nil(t, err)
+	then.Equals(t, "# "+change.Filename+"\n"+string(contents), builder.String())
+}
+
+func TestFragmentsRemoveChange(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	first := &core.Change{Project: "a", Kind: "added", Body: "first"}
+	second := &core.Change{Project: "a", Kind: "added", Body: "second"}
+	writeChangeFile(t, cfg, first)
+	writeChangeFile(t, cfg, second)
+
+	f := NewFragments(core.NewTemplateCache())
+
+	err := f.Remove(f.Command, []string{first.Filename})
+	then.Nil(t, err)
+	then.FileNotExists(t, first.Filename)
+	then.FileExists(t, second.Filename)
+}
+
+func TestFragmentsRemoveChangeWithUnknownKind(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	broken := &core.Change{Project: "a", Kind: "unknown", Body: "broken"}
+	valid := &core.Change{Project: "a", Kind: "added", Body: "valid"}
+	writeChangeFile(t, cfg, broken)
+	writeChangeFile(t, cfg, valid)
+
+	f := NewFragments(core.NewTemplateCache())
+
+	err := f.Remove(f.Command, []string{filepath.Base(broken.Filename)})
+	then.Nil(t, err)
+	then.FileNotExists(t, broken.Filename)
+	then.FileExists(t, valid.Filename)
+}
+
+func TestFragmentsRemoveWithMalformedChange(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	valid := &core.Change{Project: "a", Kind: "added", Body: "valid"}
+	writeChangeFile(t, cfg, valid)
+	then.WriteFile(t, []byte("kind: [broken"), cfg.ChangesDir, cfg.UnreleasedDir, "broken.yaml")
+
+	f := NewFragments(core.NewTemplateCache())
+
+	err := f.Remove(f.Command, []string{filepath.Base(valid.Filename)})
+	then.Nil(t, err)
+	then.FileNotExists(t, valid.Filename)
+
+	err = f.Remove(f.Command, []string{"broken"})
+	then.Nil(t, err)
+	then.FileNotExists(t, filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "broken.yaml"))
+}
+
+func TestErrorFragmentsRemoveMissingChange(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	writeChangeFile(t, cfg, &core.Change{Project: "a", Kind: "added", Body: "first"})
+
+	f := NewFragments(core.NewTemplateCache())
+
+	err := f.Remove(f.Command, []string{"missing.yaml"})
+	then.Err(t, errFragmentNotFound, err)
+}
+
+func TestFragmentsMoveChangeToProject(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	change := &core.Change{Project: "a", Kind: "added", Body: "first"}
+	writeChangeFile(t, cfg, change)
+
+	f := NewFragments(core.NewTemplateCache())
+	f.MoveProject = "B"
+
+	err := f.Move(f.Command, []string{change.Filename})
+	then.Nil(t, err)
+	then.FileNotExists(t, change.Filename)
+
+	moved, err := core.LoadChange(filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "b-added-first.yaml"))
+	then.Nil(t, err)
+	then.Equals(t, "b", moved.Project)
+	then.Equals(t, "first", moved.Body)
+}
+
+func TestFragmentsMoveChangeToProjectSubdir(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	cfg.FragmentSubdirs = true
+	cfg.FragmentFileFormat = "{{.Project}}/{{.Kind}}-{{.Body}}"
+	then.WithTempDirConfig(t, cfg)
+
+	change := &core.Change{
+		Project:  "a",
+		Kind:     "added",
+		Body:     "first",
+		Filename: filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "a", "added-first.yaml"),
+	}
+	writeChangeFile(t, cfg, change)
+
+	f := NewFragments(core.NewTemplateCache())
+	f.MoveProject = "b"
+
+	err := f.Move(f.Command, []string{change.Filename})
+	then.Nil(t, err)
+	then.FileExists(t, cfg.ChangesDir, cfg.UnreleasedDir, "b", "added-first.yaml")
+	then.FileNotExists(t, cfg.ChangesDir, cfg.UnreleasedDir, "a")
+}
+
+func TestErrorFragmentsMoveChangeWithKindNotInProject(t *testing.T) {
+	cfg := fragmentsTestConfig()
+	cfg.Projects[1].Kinds = []core.KindConfig{{Label: "Command"}}
+	then.WithTempDirConfig(t, cfg)
+
+	change := &core.Change{Project: "a", Kind: "added", Body: "first"}
+	writeChangeFile(t, cfg, change)
+
+	f := NewFragments(core.NewTemplateCache())
+	f.MoveProject = "b"
+
+	err := f.Move(f.Command, []string{change.Filename})
+	then.Err(t, core.ErrKindNotFound, err)
+	then.FileExists(t, change.Filename)
+}
+
+func TestErrorFragmentsMoveWithoutProjects(t *testing.T) {
+	cfg := batchTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	f := NewFragments(core.NewTemplateCache())
+	f.MoveProject = "b"
+
+	err := f.Move(f.Command, []string{"any"})
+	then.Err(t, errProjectsNotConfigured, err)
+}
@@ -121,6 +121,16 @@ func (g *Gen) Run(cmd *cobra.Command, args []string) error {
 	defer jsonSchemaFile.Close()
 
 	schema := jsonReflector.Reflect(&core.Config{})
+
+	// components can also be configured as plain strings
+	if componentSchema, found := schema.Definitions["ComponentConfig"]; found {
+		schema.Definitions["ComponentConfig"] = &jsonschema.Schema{
+			OneOf: []*jsonschema.Schema{
+				{Type: "string"},
+				componentSchema,
+			},
+		}
+	}
 	schemaEncoder := json.NewEncoder(jsonSchemaFile)
 	schemaEncoder.SetIndent("", "  ")
 
@@ -68,6 +68,12 @@ func (l *Latest) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		l.Project = pc.Key
+
+		// projects can override the config, such as the version extension
+		config, err = config.ForProject(pc.Key)
+		if err != nil {
+			return err
+		}
 		projPrefix = pc.Key + config.ProjectsVersionSeparator
 	}
 
@@ -154,3 +154,20 @@ func TestErrorLatestBadWrite(t *testing.T) {
 	cmd.SetOut(w)
 	w.Raised(t, cmd.Run(cmd.Command, nil))
 }
+
+func TestLatestVersionFromTags(t *testing.T) {
+	cfg := latestConfig()
+	cfg.VersionSource = core.VersionSourceTags
+	then.WithTempDirConfig(t, cfg)
+	gitTags(t, "v0.1.0", "v0.3.0")
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.1.0.md")
+
+	builder := strings.Builder{}
+	latest := NewLatest()
+	latest.SetOut(&builder)
+
+	err := latest.Run(latest.Command, nil)
+	then.Nil(t, err)
+	then.Equals(t, "v0.3.0", builder.String())
+}
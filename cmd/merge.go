@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
 
 	"github.com/miniscruff/changie/core"
@@ -17,13 +21,19 @@ type Merge struct {
 	// cli args
 	DryRun           bool
 	UnreleasedHeader string
+	Order            string
 
 	// dependencies
+	TimeNow       core.TimeNow
 	TemplateCache *core.TemplateCache
 }
 
-func NewMerge(templateCache *core.TemplateCache) *Merge {
+func NewMerge(
+	timeNow core.TimeNow,
+	templateCache *core.TemplateCache,
+) *Merge {
 	m := &Merge{
+		TimeNow:       timeNow,
 		TemplateCache: templateCache,
 	}
 
@@ -32,7 +42,17 @@ func NewMerge(templateCache *core.TemplateCache) *Merge {
 		Short: "Merge all versions into one changelog",
 		Long: `Merge all version files into one changelog file and run any replacement commands.
 
-Note that a newline is added between each version file.`,
+Note that a newline is added between each version file.
+
+Unreleased changes can be included using --include-unreleased, rendered the same as batch
+would release them with the value as the version, including the version header and footer.
+The next version predicted by auto is available as NextVersion.
+
+If projects and a combined changelog are configured, an additional changelog including
+the releases of every project is written, newest first.
+
+Versions are written in the configured version order, which can be overridden with --order.
+Archives always include the oldest versions, regardless of the order.`,
 		Args: cobra.NoArgs,
 		RunE: m.Run,
 	}
@@ -47,7 +67,13 @@ Note that a newline is added between each version file.`,
 		&m.UnreleasedHeader,
 		"include-unreleased", "u",
 		"",
-		"Include unreleased changes with this value as the header",
+		"Include unreleased changes with this value as the version, rendered like batch",
+	)
+	cmd.Flags().StringVar(
+		&m.Order,
+		"order",
+		"",
+		"Order of versions, one of descending, ascending, date-descending or date-ascending",
 	)
 
 	m.Command = cmd
@@ -64,10 +90,19 @@ func (m *Merge) Run(cmd *cobra.Command, args []string) error {
 	// If we have projects, merge all of them.
 	if len(cfg.Projects) > 0 {
 		for _, pc := range cfg.Projects {
-			err = m.mergeProject(cfg, pc.Key, pc.ChangelogPath, pc.Replacements)
+			projectCfg, err := cfg.ForProject(pc.Key)
 			if err != nil {
 				return err
 			}
+
+			err = m.mergeProject(projectCfg, pc.Key, pc.ChangelogPath, pc.Replacements)
+			if err != nil {
+				return err
+			}
+		}
+
+		if cfg.CombinedChangelog != nil {
+			return m.mergeCombined(cfg)
 		}
 
 		return nil
@@ -81,28 +116,48 @@ func (m *Merge) mergeProject(
 	project, changelogPath string,
 	replacements []core.Replacement,
 ) error {
-	var writer io.Writer
-	if m.DryRun {
-		writer = m.OutOrStdout()
-	} else {
-		dirErr := os.MkdirAll(filepath.Dir(changelogPath), core.CreateDirMode)
-		if dirErr != nil {
-			return fmt.Errorf("creating changelog file directory: %w", dirErr)
-		}
+	writer, closeWriter, err := m.changelogWriter(changelogPath)
+	if err != nil {
+		return err
+	}
 
-		changeFile, changeErr := os.Create(changelogPath)
-		if changeErr != nil {
-			return fmt.Errorf("creating changelog file: %w", changeErr)
-		}
+	defer closeWriter()
+
+	allVersions, err := core.GetFileVersions(cfg, false, project)
+	if err != nil {
+		return fmt.Errorf("finding release notes: %w", err)
+	}
+
+	keptVersions, archives := splitArchives(cfg.ChangelogArchive, allVersions)
 
-		defer changeFile.Close()
+	keptVersions, err = core.OrderVersions(cfg, project, m.Order, keptVersions)
+	if err != nil {
+		return err
+	}
 
-		writer = changeFile
+	for i := range archives {
+		archives[i].versions, err = core.OrderVersions(cfg, project, m.Order, archives[i].versions)
+		if err != nil {
+			return err
+		}
 	}
 
-	allVersions, err := core.GetAllVersions(cfg, false, project)
+	// changelog versions link to every version, including archived versions
+	allChangelogVersions, err := core.ChangelogVersions(cfg, project, allVersions)
 	if err != nil {
-		return fmt.Errorf("finding release notes: %w", err)
+		return err
+	}
+
+	changelogVersions := make([]core.ChangelogVersion, 0, len(keptVersions))
+
+	for _, version := range keptVersions {
+		changelogVersions = append(changelogVersions, allChangelogVersions[slices.Index(allVersions, version)])
+	}
+
+	changelogData := core.ChangelogData{
+		Project:  project,
+		Versions: changelogVersions,
+		Env:      cfg.EnvVars(),
 	}
 
 	if cfg.HeaderPath != "" {
@@ -110,56 +165,43 @@ func (m *Merge) mergeProject(
 		if err != nil {
 			return err
 		}
+	}
 
+	if cfg.ChangelogHeaderFormat != "" {
+		err = m.TemplateCache.Execute(cfg.ChangelogHeaderFormat, writer, changelogData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.HeaderPath != "" || cfg.ChangelogHeaderFormat != "" {
 		_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogHeader)
 	}
 
 	if m.UnreleasedHeader != "" {
-		var unrelErr error
-
-		allChanges, unrelErr := core.GetChanges(
-			cfg,
-			nil,
-			project,
-		)
-		if unrelErr != nil {
-			return unrelErr
-		}
-
-		// Make sure we have any changes before writing the unreleased content.
-		if len(allChanges) > 0 {
-			_ = core.WriteNewlines(writer, cfg.Newlines.BeforeChangelogVersion)
-			_ = core.WriteNewlines(writer, cfg.Newlines.BeforeVersion)
-			_, _ = writer.Write([]byte(m.UnreleasedHeader))
-			_ = core.WriteNewlines(writer, cfg.Newlines.AfterVersion)
-
-			// create a fake batch to write the changes
-			b := &Batch{
-				config:        cfg,
-				writer:        writer,
-				TemplateCache: m.TemplateCache,
-			}
-
-			unrelErr = b.WriteChanges(allChanges)
-			if unrelErr != nil {
-				return unrelErr
-			}
-
-			_ = core.WriteNewlines(b.writer, b.config.Newlines.EndOfVersion)
-			_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogVersion)
+		err = m.writeUnreleased(writer, cfg, project)
+		if err != nil {
+			return err
 		}
 	}
 
-	for _, version := range allVersions {
-		_ = core.WriteNewlines(writer, cfg.Newlines.BeforeChangelogVersion)
-		versionPath := filepath.Join(cfg.ChangesDir, project, version.Original()+"."+cfg.VersionExt)
+	err = writeVersions(writer, cfg, project, keptVersions)
+	if err != nil {
+		return err
+	}
 
-		err = core.AppendFile(writer, versionPath)
+	for _, archive := range archives {
+		err = m.writeArchive(writer, cfg, project, changelogPath, archive)
 		if err != nil {
 			return err
 		}
+	}
 
-		_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogVersion)
+	if cfg.ChangelogFooterFormat != "" {
+		err = m.TemplateCache.Execute(cfg.ChangelogFooterFormat, writer, changelogData)
+		if err != nil {
+			return err
+		}
 	}
 
 	if len(allVersions) == 0 {
@@ -190,3 +232,342 @@ func (m *Merge) mergeProject(
 
 	return nil
 }
+
+// writeUnreleased writes unreleased changes the same as batch would release them,
+// using the unreleased header as the version.
+func (m *Merge) writeUnreleased(writer io.Writer, cfg *core.Config, project string) error {
+	allChanges, err := core.GetChanges(cfg, nil, project)
+	if err != nil {
+		return err
+	}
+
+	// Make sure we have any changes before writing the unreleased content.
+	if len(allChanges) == 0 {
+		return nil
+	}
+
+	previousVersion, err := core.GetLatestVersion(cfg, false, project)
+	if err != nil {
+		return err
+	}
+
+	data := &core.BatchData{
+		Time:            m.TimeNow(),
+		Version:         m.UnreleasedHeader,
+		VersionNoPrefix: m.UnreleasedHeader,
+		PreviousVersion: previousVersion.Original(),
+		Changes:         allChanges,
+		Env:             cfg.EnvVars(),
+	}
+
+	nextVersion, err := core.GetNextVersion(
+		cfg, m.TemplateCache, core.AutoLevel, false, nil, nil, allChanges, project)
+
+	// the next version is only predicted if every change has an auto level
+	switch {
+	case errors.Is(err, core.ErrNoReleaseNeeded),
+		errors.Is(err, core.ErrNoChangesFoundForAuto),
+		errors.Is(err, core.ErrMissingAutoLevel):
+	case err != nil:
+		return err
+	default:
+		data.NextVersion = nextVersion.Original()
+		data.Major = int(nextVersion.Major()) //nolint:gosec
+		data.Minor = int(nextVersion.Minor()) //nolint:gosec
+		data.Patch = int(nextVersion.Patch()) //nolint:gosec
+	}
+
+	// create a fake batch to write the release
+	b := &Batch{
+		config:        cfg,
+		writer:        writer,
+		TemplateCache: m.TemplateCache,
+	}
+
+	_ = core.WriteNewlines(writer, cfg.Newlines.BeforeChangelogVersion)
+
+	err = b.writeRelease(data)
+	if err != nil {
+		return err
+	}
+
+	_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogVersion)
+
+	return nil
+}
+
+// writeVersions appends the release notes of each version.
+func writeVersions(writer io.Writer, cfg *core.Config, project string, versions []*semver.Version) error {
+	for _, version := range versions {
+		_ = core.WriteNewlines(writer, cfg.Newlines.BeforeChangelogVersion)
+
+		if cfg.ChangelogVersionAnchors {
+			_, err := fmt.Fprintf(writer, "<a name=\"%s\"></a>\n", core.VersionAnchor(version))
+			if err != nil {
+				return err
+			}
+		}
+
+		versionPath, err := core.VersionFilePath(cfg, project, version)
+		if err != nil {
+			return err
+		}
+
+		err = core.AppendVersionFile(cfg, writer, versionPath)
+		if err != nil {
+			return err
+		}
+
+		_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogVersion)
+	}
+
+	return nil
+}
+
+// changelogArchive is the versions of a single major moved out of the changelog.
+type changelogArchive struct {
+	major    int
+	versions []*semver.Version
+}
+
+// splitArchives returns the versions kept in the changelog, and the remaining versions
+// grouped by major, in the same order.
+func splitArchives(
+	archiveCfg *core.ChangelogArchiveConfig,
+	versions []*semver.Version,
+) ([]*semver.Version, []changelogArchive) {
+	if archiveCfg == nil || len(versions) == 0 {
+		return versions, nil
+	}
+
+	latestMajor := versions[0].Major()
+	for _, version := range versions {
+		latestMajor = max(latestMajor, version.Major())
+	}
+
+	kept := make([]*semver.Version, 0, len(versions))
+
+	var archives []changelogArchive
+
+	for i, version := range versions {
+		keep := (archiveCfg.KeepVersions <= 0 || i < archiveCfg.KeepVersions) &&
+			(!archiveCfg.KeepCurrentMajor || version.Major() == latestMajor)
+
+		// versions are only kept while every previous version is kept
+		if keep && len(archives) == 0 {
+			kept = append(kept, version)
+			continue
+		}
+
+		major := int(version.Major()) //nolint:gosec
+
+		idx := slices.IndexFunc(archives, func(archive changelogArchive) bool {
+			return archive.major == major
+		})
+		if idx < 0 {
+			archives = append(archives, changelogArchive{major: major})
+			idx = len(archives) - 1
+		}
+
+		archives[idx].versions = append(archives[idx].versions, version)
+	}
+
+	return kept, archives
+}
+
+// writeArchive writes the versions of an archive to its own file next to the changelog,
+// and a link to the archive in the changelog.
+func (m *Merge) writeArchive(
+	writer io.Writer,
+	cfg *core.Config,
+	project, changelogPath string,
+	archive changelogArchive,
+) error {
+	pathFormat := cfg.ChangelogArchive.PathFormat
+	if pathFormat == "" {
+		pathFormat = "CHANGELOG-{{.Major}}.x.md"
+	}
+
+	linkFormat := cfg.ChangelogArchive.LinkFormat
+	if linkFormat == "" {
+		linkFormat = "* [{{.Major}}.x]({{.Path}})"
+	}
+
+	data := core.ArchiveData{
+		Project: project,
+		Major:   archive.major,
+		Env:     cfg.EnvVars(),
+	}
+
+	archivePath, err := m.TemplateCache.ExecuteString(pathFormat, data)
+	if err != nil {
+		return err
+	}
+
+	data.Path = filepath.ToSlash(archivePath)
+
+	if !m.DryRun {
+		archiveWriter, closeArchive, err := m.changelogWriter(filepath.Join(filepath.Dir(changelogPath), archivePath))
+		if err != nil {
+			return err
+		}
+
+		defer closeArchive()
+
+		err = writeVersions(archiveWriter, cfg, project, archive.versions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = m.TemplateCache.Execute(linkFormat, writer, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write([]byte("\n"))
+
+	return err
+}
+
+// changelogWriter creates the changelog file to write to, or uses stdout for dry runs.
+// The returned func closes the changelog file once writing is done.
+func (m *Merge) changelogWriter(changelogPath string) (io.Writer, func(), error) {
+	if m.DryRun {
+		return m.OutOrStdout(), func() {}, nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(changelogPath), core.CreateDirMode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating changelog file directory: %w", err)
+	}
+
+	changeFile, err := os.Create(changelogPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating changelog file: %w", err)
+	}
+
+	return changeFile, func() { _ = changeFile.Close() }, nil
+}
+
+// combinedRelease is a single project release included in the combined changelog.
+type combinedRelease struct {
+	config *core.Config
+	path   string
+	data   core.CombinedReleaseData
+}
+
+// combinedReleases returns the releases of every project, newest first.
+func combinedReleases(cfg *core.Config) ([]combinedRelease, error) {
+	var releases []combinedRelease
+
+	for _, pc := range cfg.Projects {
+		projectCfg, err := cfg.ForProject(pc.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		versions, err := core.GetFileVersions(projectCfg, false, pc.Key)
+		if err != nil {
+			return nil, fmt.Errorf("finding release notes: %w", err)
+		}
+
+		for _, version := range versions {
+			versionPath, err := core.VersionFilePath(projectCfg, pc.Key, version)
+			if err != nil {
+				return nil, err
+			}
+
+			released, err := core.VersionTime(projectCfg, versionPath)
+			if err != nil {
+				return nil, err
+			}
+
+			releases = append(releases, combinedRelease{
+				config: projectCfg,
+				path:   versionPath,
+				data: core.CombinedReleaseData{
+					Time:            released,
+					Project:         pc.Label,
+					ProjectKey:      pc.Key,
+					Version:         version.Original(),
+					VersionNoPrefix: version.String(),
+					Env:             cfg.EnvVars(),
+				},
+			})
+		}
+	}
+
+	// stable sort keeps the project and version order for releases of the same time
+	slices.SortStableFunc(releases, func(a, b combinedRelease) int {
+		return b.data.Time.Compare(a.data.Time)
+	})
+
+	return releases, nil
+}
+
+// mergeCombined writes the combined changelog of every project.
+func (m *Merge) mergeCombined(cfg *core.Config) error {
+	combined := cfg.CombinedChangelog
+
+	releases, err := combinedReleases(cfg)
+	if err != nil {
+		return err
+	}
+
+	writer, closeWriter, err := m.changelogWriter(combined.Path)
+	if err != nil {
+		return err
+	}
+
+	defer closeWriter()
+
+	if combined.HeaderPath != "" {
+		err = core.AppendFile(writer, filepath.Join(cfg.ChangesDir, combined.HeaderPath))
+		if err != nil {
+			return err
+		}
+
+		_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogHeader)
+	}
+
+	lastDate := ""
+
+	for _, release := range releases {
+		if combined.DateFormat != "" {
+			var date strings.Builder
+
+			err = m.TemplateCache.Execute(combined.DateFormat, &date, release.data)
+			if err != nil {
+				return err
+			}
+
+			if date.String() != lastDate {
+				lastDate = date.String()
+
+				_ = core.WriteNewlines(writer, cfg.Newlines.BeforeChangelogVersion)
+				_, _ = writer.Write([]byte(lastDate + "\n"))
+			}
+		}
+
+		_ = core.WriteNewlines(writer, cfg.Newlines.BeforeChangelogVersion)
+
+		if combined.ReleaseFormat != "" {
+			err = m.TemplateCache.Execute(combined.ReleaseFormat, writer, release.data)
+			if err != nil {
+				return err
+			}
+
+			_, _ = writer.Write([]byte("\n"))
+		}
+
+		err = core.AppendVersionFile(release.config, writer, release.path)
+		if err != nil {
+			return err
+		}
+
+		_ = core.WriteNewlines(writer, cfg.Newlines.AfterChangelogVersion)
+	}
+
+	return nil
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/miniscruff/changie/core"
 	"github.com/miniscruff/changie/then"
@@ -51,7 +53,7 @@ func TestMergeVersionsSuccessfully(t *testing.T) {
 	then.WriteFile(t, []byte("first version\n"), cfg.ChangesDir, "v0.1.0.md")
 	then.WriteFile(t, []byte("second version\n"), cfg.ChangesDir, "v0.2.0.md")
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 	err := cmd.Run(cmd.Command, nil)
 	then.Nil(t, err)
 
@@ -61,6 +63,65 @@ first version
 	then.FileContents(t, changeContents, "news.md")
 }
 
+func TestMergeVersionsWithVersionFileFormat(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.VersionFileFormat = "{{.Version}}/notes.md"
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("first version\n"), cfg.ChangesDir, "v0.1.0", "notes.md")
+	then.WriteFile(t, []byte("second version\n"), cfg.ChangesDir, "v0.2.0", "notes.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `second version
+first version
+`
+	then.FileContents(t, changeContents, "news.md")
+}
+
+func TestMergeVersionsWithChangelogHeaderAndFooter(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.ChangelogHeaderFormat = `{{ range .Versions -}}
+* [{{.Version}}](#{{.Anchor}})
+{{ end -}}
+`
+	cfg.ChangelogFooterFormat = `{{- range .Versions }}
+{{- if .PreviousVersion }}
+[{{.VersionNoPrefix}}]: https://example.com/compare/{{.PreviousVersion}}...{{.Version}}
+{{- end}}
+{{- end}}
+`
+	cfg.Newlines.AfterChangelogHeader = 1
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n"), cfg.ChangesDir, "v0.1.0.md")
+	then.WriteFile(t, []byte("## v0.2.0\n"), cfg.ChangesDir, "v0.2.0.md")
+	then.WriteFile(t, []byte("## v1.0.0\n"), cfg.ChangesDir, "v1.0.0.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `* [v1.0.0](#v100)
+* [v0.2.0](#v020)
+* [v0.1.0](#v010)
+
+## v1.0.0
+## v0.2.0
+## v0.1.0
+
+[1.0.0]: https://example.com/compare/v0.2.0...v1.0.0
+[0.2.0]: https://example.com/compare/v0.1.0...v0.2.0
+`
+	then.FileContents(t, changeContents, "news.md")
+}
+
 func TestMergeVersionsSuccessfullyWithProject(t *testing.T) {
 	cfg := mergeTestConfig()
 	cfg.HeaderPath = ""
@@ -85,7 +146,7 @@ func TestMergeVersionsSuccessfullyWithProject(t *testing.T) {
 	then.WriteFile(t, []byte("second version\n"), cfg.ChangesDir, "a", "v0.2.0.md")
 	then.WriteFile(t, []byte("version\n"), "a", "VERSION")
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 
 	err := cmd.Run(cmd.Command, nil)
 	then.Nil(t, err)
@@ -110,7 +171,7 @@ func TestMergeVersionsSuccessfullyWithProjectAndNoChanges(t *testing.T) {
 	}
 	then.WithTempDirConfig(t, cfg)
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 
 	err := cmd.Run(cmd.Command, nil)
 	then.Nil(t, err)
@@ -134,8 +195,8 @@ func TestMergeVersionsWithUnreleasedChanges(t *testing.T) {
 	}
 	writeChangeFile(t, cfg, &unrel)
 
-	cmd := NewMerge(core.NewTemplateCache())
-	cmd.UnreleasedHeader = "## Coming Soon"
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	cmd.UnreleasedHeader = "Coming Soon"
 	err := cmd.Run(cmd.Command, nil)
 	then.Nil(t, err)
 
@@ -148,6 +209,70 @@ first version
 	then.FileContents(t, changeContents, "news.md")
 }
 
+func TestMergeVersionsWithUnreleasedChangesRenderedLikeBatch(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.VersionFrontmatter = true
+	cfg.VersionHeaderPath = "header.md"
+	cfg.HeaderFormat = "Predicted {{.NextVersion}} after {{.PreviousVersion}} on {{.Time.Format \"2006-01-02\"}}"
+	cfg.FooterFormat = "Patch {{.Patch}}"
+	cfg.Kinds[0].AutoLevel = core.PatchLevel
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n"), cfg.ChangesDir, "v0.1.0.md")
+	then.WriteFile(t, []byte("version header"), cfg.ChangesDir, cfg.UnreleasedDir, "header.md")
+
+	unrel := core.Change{
+		Kind: "Added",
+		Body: "new feature coming soon",
+	}
+	writeChangeFile(t, cfg, &unrel)
+
+	cmd := NewMerge(func() time.Time {
+		return time.Date(2026, 5, 6, 10, 0, 0, 0, time.UTC)
+	}, core.NewTemplateCache())
+	cmd.UnreleasedHeader = "Unreleased"
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `## Unreleased
+version header
+Predicted v0.1.1 after v0.1.0 on 2026-05-06
+### Added
+* new feature coming soon
+
+Patch 1## v0.1.0
+`
+	then.FileContents(t, changeContents, "news.md")
+}
+
+func TestMergeVersionsWithUnreleasedHeaderIncludingVersionFormat(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	then.WithTempDirConfig(t, cfg)
+
+	unrel := core.Change{
+		Kind: "Added",
+		Body: "new feature coming soon",
+	}
+	writeChangeFile(t, cfg, &unrel)
+
+	// The header is rendered through the version format, so values that
+	// already include the format are formatted twice.
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	cmd.UnreleasedHeader = "## Coming Soon"
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `## ## Coming Soon
+### Added
+* new feature coming soon
+`
+	then.FileContents(t, changeContents, "news.md")
+}
+
 func TestMergeVersionsWithUnreleasedChangesErrorsOnBadChanges(t *testing.T) {
 	cfg := mergeTestConfig()
 	cfg.HeaderPath = ""
@@ -160,8 +285,8 @@ func TestMergeVersionsWithUnreleasedChangesErrorsOnBadChanges(t *testing.T) {
 	aVer := []byte("not a valid change")
 	then.WriteFile(t, aVer, cfg.ChangesDir, cfg.UnreleasedDir, "a.yaml")
 
-	cmd := NewMerge(core.NewTemplateCache())
-	cmd.UnreleasedHeader = "## Coming Soon"
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	cmd.UnreleasedHeader = "Coming Soon"
 	err := cmd.Run(cmd.Command, nil)
 	then.NotNil(t, err)
 }
@@ -182,8 +307,8 @@ func TestMergeVersionsWithUnreleasedChangesErrorsOnBadChangeFormat(t *testing.T)
 	}
 	writeChangeFile(t, cfg, &unrel)
 
-	cmd := NewMerge(core.NewTemplateCache())
-	cmd.UnreleasedHeader = "## Coming Soon"
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	cmd.UnreleasedHeader = "Coming Soon"
 	err := cmd.Run(cmd.Command, nil)
 	then.NotNil(t, err)
 }
@@ -220,8 +345,8 @@ func TestMergeVersionsWithUnreleasedChangesInOneProject(t *testing.T) {
 	}
 	writeChangeFile(t, cfg, &unrel)
 
-	cmd := NewMerge(core.NewTemplateCache())
-	cmd.UnreleasedHeader = "## Coming Soon"
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	cmd.UnreleasedHeader = "Coming Soon"
 	err := cmd.Run(cmd.Command, nil)
 	then.Nil(t, err)
 
@@ -239,6 +364,133 @@ first B version
 	then.FileContents(t, changeContentsB, "b", "thing", "CHANGELOG.md")
 }
 
+func TestMergeVersionsWithChangelogVersionAnchors(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.ChangelogVersionAnchors = true
+	cfg.ChangelogHeaderFormat = `{{ range .Versions -}}
+* [{{.Version}}](#{{.Anchor}})
+{{ end -}}
+`
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0 - 2024-01-02\n"), cfg.ChangesDir, "v0.1.0.md")
+	then.WriteFile(t, []byte("## v0.2.0 - 2024-02-03\n"), cfg.ChangesDir, "v0.2.0.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `* [v0.2.0](#v020)
+* [v0.1.0](#v010)
+<a name="v020"></a>
+## v0.2.0 - 2024-02-03
+<a name="v010"></a>
+## v0.1.0 - 2024-01-02
+`
+	then.FileContents(t, changeContents, "news.md")
+}
+
+func TestMergeVersionsWithArchiveKeepCurrentMajor(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.ChangelogArchive = &core.ChangelogArchiveConfig{
+		KeepCurrentMajor: true,
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n"), cfg.ChangesDir, "v0.1.0.md")
+	then.WriteFile(t, []byte("## v1.0.0\n"), cfg.ChangesDir, "v1.0.0.md")
+	then.WriteFile(t, []byte("## v1.1.0\n"), cfg.ChangesDir, "v1.1.0.md")
+	then.WriteFile(t, []byte("## v2.0.0\n"), cfg.ChangesDir, "v2.0.0.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	then.FileContents(t, `## v2.0.0
+* [1.x](CHANGELOG-1.x.md)
+* [0.x](CHANGELOG-0.x.md)
+`, "news.md")
+	then.FileContents(t, "## v1.1.0\n## v1.0.0\n", "CHANGELOG-1.x.md")
+	then.FileContents(t, "## v0.1.0\n", "CHANGELOG-0.x.md")
+}
+
+func TestMergeVersionsWithArchiveKeepVersions(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.ChangelogArchive = &core.ChangelogArchiveConfig{
+		KeepVersions: 2,
+		PathFormat:   "archive/v{{.Major}}.md",
+		LinkFormat:   "See [version {{.Major}}]({{.Path}})",
+	}
+	cfg.ChangelogFooterFormat = `{{- range .Versions }}
+[{{.VersionNoPrefix}}]: https://example.com/compare/{{.PreviousVersion}}...{{.Version}}
+{{- end}}
+`
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n"), cfg.ChangesDir, "v0.1.0.md")
+	then.WriteFile(t, []byte("## v1.0.0\n"), cfg.ChangesDir, "v1.0.0.md")
+	then.WriteFile(t, []byte("## v1.1.0\n"), cfg.ChangesDir, "v1.1.0.md")
+	then.WriteFile(t, []byte("## v1.2.0\n"), cfg.ChangesDir, "v1.2.0.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	then.FileContents(t, `## v1.2.0
+## v1.1.0
+See [version 1](archive/v1.md)
+See [version 0](archive/v0.md)
+
+[1.2.0]: https://example.com/compare/v1.1.0...v1.2.0
+[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`, "news.md")
+	then.FileContents(t, "## v1.0.0\n", "archive", "v1.md")
+	then.FileContents(t, "## v0.1.0\n", "archive", "v0.md")
+}
+
+func TestMergeVersionsAscending(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.VersionOrder = core.VersionOrderAscending
+	cfg.ChangelogFooterFormat = `{{- range .Versions }}
+[{{.VersionNoPrefix}}]: {{.PreviousVersion}}...{{.Version}}
+{{- end}}
+`
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("## v0.1.0\n"), cfg.ChangesDir, "v0.1.0.md")
+	then.WriteFile(t, []byte("## v0.2.0\n"), cfg.ChangesDir, "v0.2.0.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	then.FileContents(t, `## v0.1.0
+## v0.2.0
+
+[0.1.0]: ...v0.1.0
+[0.2.0]: v0.1.0...v0.2.0
+`, "news.md")
+}
+
+func TestErrorMergeBadOrder(t *testing.T) {
+	cfg := mergeTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	cmd.Order = "newest"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Err(t, core.ErrVersionOrder, err)
+}
+
 func TestMergeVersionsWithHeaderAndReplacements(t *testing.T) {
 	cfg := mergeTestConfig()
 	then.WithTempDirConfig(t, cfg)
@@ -254,7 +506,7 @@ func TestMergeVersionsWithHeaderAndReplacements(t *testing.T) {
 	then.WriteFile(t, []byte("a simple header\n"), cfg.ChangesDir, cfg.HeaderPath)
 	then.WriteFile(t, []byte(jsonContents), "replace.json")
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 	err := cmd.Run(cmd.Command, nil)
 	then.Nil(t, err)
 
@@ -286,7 +538,7 @@ first version
 	then.WriteFile(t, []byte("ignored\n"), cfg.ChangesDir, "ignored.txt")
 	then.WriteFile(t, []byte("a simple header\n"), cfg.ChangesDir, cfg.HeaderPath)
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 	cmd.DryRun = true
 	cmd.SetOut(&writer)
 	err := cmd.Run(cmd.Command, nil)
@@ -303,7 +555,7 @@ func TestMergeSkipsVersionsIfNoneFound(t *testing.T) {
 
 	then.WriteFile(t, []byte("a simple header\n"), cfg.ChangesDir, cfg.HeaderPath)
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 	cmd.DryRun = true
 	cmd.SetOut(&builder)
 
@@ -312,10 +564,140 @@ func TestMergeSkipsVersionsIfNoneFound(t *testing.T) {
 	then.Equals(t, changeContents, builder.String())
 }
 
+func TestMergeCombinedChangelog(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "Api", Key: "api", ChangelogPath: "api/CHANGELOG.md"},
+		{Label: "Web", Key: "web", ChangelogPath: "web/CHANGELOG.md"},
+	}
+	cfg.CombinedChangelog = &core.CombinedChangelogConfig{
+		Path:          "CHANGELOG.md",
+		HeaderPath:    "combined.md",
+		DateFormat:    `# {{.Time.Format "2006-01-02"}}`,
+		ReleaseFormat: "## {{.Project}} ({{.ProjectKey}}) {{.Version}}",
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("all projects\n"), cfg.ChangesDir, "combined.md")
+	then.WriteFile(t, []byte("api one\n"), cfg.ChangesDir, "api", "v0.1.0.md")
+	then.WriteFile(t, []byte("api two\n"), cfg.ChangesDir, "api", "v0.2.0.md")
+	then.WriteFile(t, []byte("web one\n"), cfg.ChangesDir, "web", "v1.0.0.md")
+
+	releaseTimes := map[string]time.Time{
+		filepath.Join("api", "v0.1.0.md"): time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local),
+		filepath.Join("web", "v1.0.0.md"): time.Date(2024, 1, 2, 10, 0, 0, 0, time.Local),
+		filepath.Join("api", "v0.2.0.md"): time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local),
+	}
+	for path, releaseTime := range releaseTimes {
+		err := os.Chtimes(filepath.Join(cfg.ChangesDir, path), releaseTime, releaseTime)
+		then.Nil(t, err)
+	}
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `all projects
+# 2024-01-02
+## Api (api) v0.2.0
+api two
+## Web (web) v1.0.0
+web one
+# 2024-01-01
+## Api (api) v0.1.0
+api one
+`
+	then.FileContents(t, changeContents, "CHANGELOG.md")
+	then.FileContents(t, "api two\napi one\n", "api", "CHANGELOG.md")
+}
+
+func TestMergeCombinedChangelogUsesCommitDates(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "Api", Key: "api", ChangelogPath: "api/CHANGELOG.md"},
+		{Label: "Web", Key: "web", ChangelogPath: "web/CHANGELOG.md"},
+	}
+	cfg.CombinedChangelog = &core.CombinedChangelogConfig{
+		Path:          "CHANGELOG.md",
+		ReleaseFormat: `## {{.Project}} {{.Version}} {{.Time.UTC.Format "2006-01-02"}}`,
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	git := func(date string, args ...string) {
+		gitCmd := exec.Command("git", append([]string{"-c", "user.name=a", "-c", "user.email=a@b.c"}, args...)...)
+		gitCmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		err := gitCmd.Run()
+		then.Nil(t, err)
+	}
+
+	git("", "init", "-q")
+
+	for _, release := range []struct {
+		path string
+		date string
+	}{
+		{filepath.Join(cfg.ChangesDir, "api", "v0.1.0.md"), "2024-01-01T10:00:00Z"},
+		{filepath.Join(cfg.ChangesDir, "web", "v1.0.0.md"), "2024-01-02T10:00:00Z"},
+		{filepath.Join(cfg.ChangesDir, "api", "v0.2.0.md"), "2024-01-03T10:00:00Z"},
+	} {
+		then.WriteFile(t, []byte(release.path+"\n"), release.path)
+		git(release.date, "add", release.path)
+		git(release.date, "commit", "-q", "-m", release.path)
+	}
+
+	// a fresh checkout gives every version file the same modification time
+	checkout := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, path := range []string{"api/v0.1.0.md", "api/v0.2.0.md", "web/v1.0.0.md"} {
+		err := os.Chtimes(filepath.Join(cfg.ChangesDir, path), checkout, checkout)
+		then.Nil(t, err)
+	}
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContents := `## Api v0.2.0 2024-01-03
+news/api/v0.2.0.md
+## Web v1.0.0 2024-01-02
+news/web/v1.0.0.md
+## Api v0.1.0 2024-01-01
+news/api/v0.1.0.md
+`
+	then.FileContents(t, changeContents, "CHANGELOG.md")
+}
+
+func TestMergeCombinedChangelogBadDateFormat(t *testing.T) {
+	cfg := mergeTestConfig()
+	cfg.HeaderPath = ""
+	cfg.Replacements = nil
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "Api", Key: "api", ChangelogPath: "api/CHANGELOG.md"},
+	}
+	cfg.CombinedChangelog = &core.CombinedChangelogConfig{
+		Path:       "CHANGELOG.md",
+		DateFormat: "{{bad....}}",
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte("api one\n"), cfg.ChangesDir, "api", "v0.1.0.md")
+
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
+	err := cmd.Run(cmd.Command, nil)
+	then.NotNil(t, err)
+}
+
 func TestErrorMergeBadConfig(t *testing.T) {
 	then.WithTempDir(t)
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 	err := cmd.Run(cmd.Command, nil)
 	then.NotNil(t, err)
 }
@@ -328,7 +710,7 @@ func TestErrorMergeBadReplacement(t *testing.T) {
 	then.WriteFile(t, []byte("a simple header\n"), cfg.ChangesDir, cfg.HeaderPath)
 	then.WriteFile(t, []byte("first version\n"), cfg.ChangesDir, "v0.1.0.md")
 
-	cmd := NewMerge(core.NewTemplateCache())
+	cmd := NewMerge(time.Now, core.NewTemplateCache())
 	err := cmd.Run(cmd.Command, nil)
 	then.NotNil(t, err)
 }
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miniscruff/changie/core"
+)
+
+type MigrateKinds struct {
+	*cobra.Command
+
+	// cli args
+	DryRun      bool
+	IncludeDirs []string
+}
+
+func NewMigrateKinds() *MigrateKinds {
+	m := &MigrateKinds{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate-kinds",
+		Short: "Rewrite change fragments that use kind, component or project aliases",
+		Long: `Rewrite unreleased change fragments that use a kind, component or project alias.
+
+After renaming a kind, component or project, add the old name as an alias so existing
+fragments keep working, then run this command to update the fragments to the new name.
+Each rewritten fragment is printed along with the values that changed.`,
+		Args: cobra.NoArgs,
+		RunE: m.Run,
+	}
+
+	cmd.Flags().BoolVarP(
+		&m.DryRun,
+		"dry-run", "d",
+		false,
+		"Print the fragments that would be rewritten instead of writing to disk",
+	)
+	cmd.Flags().StringSliceVarP(
+		&m.IncludeDirs,
+		"include", "i",
+		nil,
+		"Include extra directories to search for change files, relative to change directory",
+	)
+
+	m.Command = cmd
+
+	return m
+}
+
+func (m *MigrateKinds) Run(cmd *cobra.Command, args []string) error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	changeFiles, err := core.FindChangeFiles(cfg, m.IncludeDirs)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range changeFiles {
+		change, err := core.LoadChange(path)
+		if err != nil {
+			return err
+		}
+
+		// clone projects, as resolving aliases replaces them in place
+		original := change
+		original.Projects = slices.Clone(change.Projects)
+
+		if !core.ResolveAliases(cfg, &change) {
+			continue
+		}
+
+		_, err = fmt.Fprintf(
+			cmd.OutOrStdout(),
+			"%s: %s\n",
+			path,
+			migratedValues(original, change),
+		)
+		if err != nil {
+			return err
+		}
+
+		if m.DryRun {
+			continue
+		}
+
+		err = writeChangeTo(&change, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migratedValues describes the values that changed between two changes,
+// such as "kind Feature -> Added".
+func migratedValues(before, after core.Change) string {
+	var desc string
+
+	for _, value := range []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{"project", before.Project, after.Project},
+		{"projects", strings.Join(before.Projects, ","), strings.Join(after.Projects, ",")},
+		{"component", before.Component, after.Component},
+		{"kind", before.Kind, after.Kind},
+	} {
+		if value.before == value.after {
+			continue
+		}
+
+		if desc != "" {
+			desc += ", "
+		}
+
+		desc += fmt.Sprintf("%s %s -> %s", value.name, value.before, value.after)
+	}
+
+	return desc
+}
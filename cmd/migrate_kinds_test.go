@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/miniscruff/changie/core"
+	"github.com/miniscruff/changie/then"
+)
+
+func migrateKindsTestConfig() *core.Config {
+	cfg := batchTestConfig()
+	cfg.Kinds[0].Aliases = []string{"feature"}
+	cfg.Components = []core.ComponentConfig{{Label: "api", Aliases: []string{"backend"}}}
+
+	return cfg
+}
+
+func TestMigrateKindsRewritesAliases(t *testing.T) {
+	cfg := migrateKindsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	aliased := core.Change{Kind: "feature", Component: "backend", Body: "aliased"}
+	writeChangeFile(t, cfg, &aliased)
+
+	current := core.Change{Kind: "removed", Component: "api", Body: "current"}
+	writeChangeFile(t, cfg, &current)
+
+	var builder strings.Builder
+
+	cmd := NewMigrateKinds()
+	cmd.SetOut(&builder)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+	then.Equals(
+		t,
+		fmt.Sprintf("%s: component backend -> api, kind feature -> added\n", aliased.Filename),
+		builder.String(),
+	)
+
+	migrated, err := core.LoadChange(aliased.Filename)
+	then.Nil(t, err)
+	then.Equals(t, "added", migrated.Kind)
+	then.Equals(t, "api", migrated.Component)
+	then.Equals(t, "aliased", migrated.Body)
+	then.True(t, aliased.Time.Equal(migrated.Time))
+}
+
+func TestMigrateKindsUsesProjectAliases(t *testing.T) {
+	cfg := migrateKindsTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{
+			Label:      "CLI",
+			Key:        "cli",
+			Kinds:      []core.KindConfig{{Label: "Command", Aliases: []string{"cmd"}}},
+			Components: []core.ComponentConfig{{Label: "flags", Aliases: []string{"options"}}},
+		},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	aliased := core.Change{Project: "cli", Kind: "cmd", Component: "options", Body: "aliased"}
+	writeChangeFile(t, cfg, &aliased)
+
+	cmd := NewMigrateKinds()
+	cmd.SetOut(&strings.Builder{})
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	migrated, err := core.LoadChange(aliased.Filename)
+	then.Nil(t, err)
+	then.Equals(t, "Command", migrated.Kind)
+	then.Equals(t, "flags", migrated.Component)
+}
+
+func TestMigrateKindsReportsSharedProjectAliases(t *testing.T) {
+	cfg := migrateKindsTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "CLI", Key: "cli", Aliases: []string{"command"}},
+		{Label: "API", Key: "api"},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	aliased := core.Change{Projects: []string{"command", "api"}, Kind: "added", Body: "shared"}
+	writeChangeFile(t, cfg, &aliased)
+
+	var builder strings.Builder
+
+	cmd := NewMigrateKinds()
+	cmd.SetOut(&builder)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+	then.Equals(
+		t,
+		fmt.Sprintf("%s: projects command,api -> cli,api\n", aliased.Filename),
+		builder.String(),
+	)
+
+	migrated, err := core.LoadChange(aliased.Filename)
+	then.Nil(t, err)
+	then.SliceEquals(t, []string{"cli", "api"}, migrated.Projects)
+}
+
+func TestMigrateKindsDryRunDoesNotWrite(t *testing.T) {
+	cfg := migrateKindsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	aliased := core.Change{Kind: "feature", Body: "aliased"}
+	writeChangeFile(t, cfg, &aliased)
+
+	var builder strings.Builder
+
+	cmd := NewMigrateKinds()
+	cmd.DryRun = true
+	cmd.SetOut(&builder)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+	then.Equals(t, fmt.Sprintf("%s: kind feature -> added\n", aliased.Filename), builder.String())
+
+	unchanged, err := core.LoadChange(aliased.Filename)
+	then.Nil(t, err)
+	then.Equals(t, "feature", unchanged.Kind)
+}
+
+func TestMigrateKindsSearchesIncludeDirs(t *testing.T) {
+	cfg := migrateKindsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	aliased := core.Change{
+		Kind:     "feature",
+		Body:     "aliased",
+		Filename: filepath.Join(cfg.ChangesDir, "extra", "a.yaml"),
+	}
+	writeChangeFile(t, cfg, &aliased)
+	then.CreateFile(t, cfg.ChangesDir, cfg.UnreleasedDir, ".gitkeep")
+
+	cmd := NewMigrateKinds()
+	cmd.IncludeDirs = []string{"extra"}
+	cmd.SetOut(&strings.Builder{})
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	migrated, err := core.LoadChange(aliased.Filename)
+	then.Nil(t, err)
+	then.Equals(t, "added", migrated.Kind)
+}
+
+func TestErrorMigrateKindsBadConfig(t *testing.T) {
+	then.WithTempDir(t)
+	then.WriteFile(t, []byte("not a proper config"), core.ConfigPaths[0])
+
+	cmd := NewMigrateKinds()
+	err := cmd.Run(cmd.Command, nil)
+	then.NotNil(t, err)
+}
+
+func TestErrorMigrateKindsBadFragment(t *testing.T) {
+	cfg := migrateKindsTestConfig()
+	then.WithTempDirConfig(t, cfg)
+	then.WriteFile(t, []byte("not a yaml file---"), cfg.ChangesDir, cfg.UnreleasedDir, "a.yaml")
+
+	cmd := NewMigrateKinds()
+	err := cmd.Run(cmd.Command, nil)
+	then.NotNil(t, err)
+}
@@ -1,16 +1,30 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/miniscruff/changie/core"
 )
 
+// newEntry is a single change read when creating changes from a file.
+type newEntry struct {
+	Project   string            `yaml:"project"`
+	Projects  []string          `yaml:"projects"`
+	Component string            `yaml:"component"`
+	Kind      string            `yaml:"kind"`
+	Body      string            `yaml:"body"`
+	Custom    map[string]string `yaml:"custom"`
+}
+
 type New struct {
 	*cobra.Command
 
@@ -23,10 +37,14 @@ type New struct {
 	BodyEditor  bool
 	Custom      []string
 	Interactive bool
+	SkipReview  bool
+	FromFile    string
+	BaseRef     string
 
 	// dependencies
 	TimeNow       core.TimeNow
 	TemplateCache *core.TemplateCache
+	ChangedFiles  core.ChangedFiles
 }
 
 func NewNew(
@@ -36,6 +54,7 @@ func NewNew(
 	n := &New{
 		TimeNow:       timeNow,
 		TemplateCache: templateCache,
+		ChangedFiles:  core.GitChangedFiles,
 	}
 
 	cmd := &cobra.Command{
@@ -54,7 +73,25 @@ Example: "CHANGIE_CUSTOM_Author=miniscruff"
 
 1. CI env var is true
 2. --interactive=false
+
+Before writing, the new changes and their file paths are shown for review.
+From there you can confirm the changes, edit a value or cancel.
+Review is skipped when prompts are disabled or with --skip-review.
+
+Multiple changes can be created at once from a JSON or YAML file, or stdin
+using "-", containing a list of changes with the fields: project or projects,
+component, kind, body and custom.
+Every change is validated before any are written, so either all changes
+are created or none are.
+
+If projects or components are configured with paths, the files changed since
+--base-ref and any staged files are compared against those paths.
+Matching projects and components are pre-selected in the prompts, or used
+as values when prompts are disabled and no flag was provided.
+Detection is skipped if git is unable to find the changed files.
 `,
+		Example: `changie new --from-file changes.json
+echo '[{"kind": "Added", "body": "New feature"}]' | changie new --from-file -`,
 		Args: cobra.NoArgs,
 		RunE: n.Run,
 	}
@@ -108,6 +145,28 @@ Example: "CHANGIE_CUSTOM_Author=miniscruff"
 		true,
 		"Set missing values with prompts",
 	)
+	cmd.Flags().BoolVar(
+		&n.SkipReview,
+		"skip-review",
+		false,
+		"Skip reviewing the new changes before writing them",
+	)
+	cmd.Flags().StringVarP(
+		&n.FromFile,
+		"from-file", "f",
+		"",
+		"Create changes from a JSON or YAML file, or '-' for stdin, instead of prompts",
+	)
+	cmd.Flags().StringVar(
+		&n.BaseRef,
+		"base-ref",
+		"",
+		"Git ref to compare against when detecting projects and components from changed files",
+	)
+
+	for _, flag := range []string{"projects", "component", "kind", "body", "editor", "custom"} {
+		cmd.MarkFlagsMutuallyExclusive("from-file", flag)
+	}
 
 	n.Command = cmd
 
@@ -120,6 +179,15 @@ func (n *New) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if n.FromFile != "" {
+		changes, err := n.changesFromFile(config)
+		if err != nil {
+			return err
+		}
+
+		return n.writeChanges(config, changes)
+	}
+
 	customValues, err := core.CustomMapFromStrings(n.Custom)
 	if err != nil {
 		return err
@@ -145,59 +213,270 @@ func (n *New) Run(cmd *cobra.Command, args []string) error {
 		Customs:          customValues,
 		EditorCmdBuilder: core.BuildCommand,
 		Enabled:          n.parsePromptEnabled(),
+		Review:           !n.SkipReview,
+		TemplateCache:    n.TemplateCache,
 	}
 
+	n.detectFromChangedFiles(config, prompts)
+
 	changes, err := prompts.BuildChanges()
 	if err != nil {
 		return err
 	}
 
-	for _, change := range changes {
-		var writer io.Writer
+	return n.writeChanges(config, changes)
+}
 
-		if n.DryRun {
-			writer = n.OutOrStdout()
-		} else {
-			var fragmentWriter strings.Builder
+// detectFromChangedFiles finds projects and components with paths matching the changed files.
+// Matches are used as defaults for the prompts, or as values if prompts are disabled.
+// Detection is optional, so if the changed files can not be found no defaults are set.
+func (n *New) detectFromChangedFiles(config *core.Config, prompts *core.Prompts) {
+	detectProjects := len(prompts.Projects) == 0 && hasProjectPaths(config)
+	detectComponent := prompts.Component == "" && hasComponentPaths(config)
 
-			fileErr := n.TemplateCache.Execute(config.FragmentFileFormat, &fragmentWriter, change)
-			if fileErr != nil {
-				return fileErr
-			}
+	if !detectProjects && !detectComponent {
+		return
+	}
+
+	files, err := n.ChangedFiles(n.BaseRef)
+	if err != nil {
+		_, _ = fmt.Fprintf(n.ErrOrStderr(), "warning: unable to detect projects and components: %v\n", err)
+		return
+	}
+
+	detected := &core.Change{
+		Projects: prompts.Projects,
+	}
+
+	if detectProjects {
+		detected.Projects = config.ProjectsForPaths(files)
+	}
+
+	// components can be overridden by the project
+	componentConfig := config
+	if len(detected.Projects) > 0 {
+		if projectConfig, err := config.ForProject(detected.Projects[0]); err == nil {
+			componentConfig = projectConfig
+		}
+	}
+
+	var components []string
+	if detectComponent {
+		components = componentConfig.ComponentsForPaths(files)
+	}
+
+	if len(components) > 0 {
+		detected.Component = components[0]
+	}
+
+	if prompts.Enabled {
+		prompts.Defaults = detected
+		return
+	}
 
-			fragmentWriter.WriteString(".yaml")
-			outputFilename := fragmentWriter.String()
+	prompts.Projects = detected.Projects
 
-			// Sanatize the filename to remove invalid characters such as slashes
-			replacer := strings.NewReplacer("/", "-", "\\", "-")
-			outputFilename = replacer.Replace(outputFilename)
+	// only fill in the component if the match is not ambiguous
+	if len(components) == 1 {
+		prompts.Component = detected.Component
+	}
+}
+
+// hasProjectPaths returns whether any project is configured with paths.
+func hasProjectPaths(config *core.Config) bool {
+	for _, pc := range config.Projects {
+		if len(pc.Paths) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
 
-			outputPath := filepath.Join(config.ChangesDir, config.UnreleasedDir, outputFilename)
+// hasComponentPaths returns whether any component, including project components,
+// is configured with paths.
+func hasComponentPaths(config *core.Config) bool {
+	for _, cc := range config.Components {
+		if len(cc.Paths) > 0 {
+			return true
+		}
+	}
 
-			fileErr = os.MkdirAll(filepath.Dir(outputPath), core.CreateDirMode)
-			if fileErr != nil {
-				return fileErr
+	for _, pc := range config.Projects {
+		for _, cc := range pc.Components {
+			if len(cc.Paths) > 0 {
+				return true
 			}
+		}
+	}
+
+	return false
+}
+
+// changesFromFile reads a list of change entries from a JSON or YAML file, or stdin,
+// validating every entry before returning any changes.
+func (n *New) changesFromFile(config *core.Config) ([]*core.Change, error) {
+	var (
+		bs  []byte
+		err error
+	)
+
+	if n.FromFile == "-" {
+		bs, err = io.ReadAll(n.InOrStdin())
+	} else {
+		bs, err = os.ReadFile(n.FromFile)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading changes file: %w", err)
+	}
+
+	// JSON is valid YAML, so we can parse both formats the same way.
+	var entries []newEntry
+
+	err = yaml.Unmarshal(bs, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("parsing changes file: %w", err)
+	}
+
+	var (
+		changes []*core.Change
+		errs    []error
+	)
+
+	for i, entry := range entries {
+		projects := entry.Projects
+		if entry.Project != "" {
+			projects = append(projects, entry.Project)
+		}
+
+		prompts := &core.Prompts{
+			Projects:  projects,
+			Component: entry.Component,
+			Kind:      entry.Kind,
+			Body:      entry.Body,
+			Customs:   entry.Custom,
+			TimeNow:   n.TimeNow,
+			Config:    config,
+			Enabled:   false,
+		}
+
+		entryChanges, err := prompts.BuildChanges()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i, err))
+			continue
+		}
+
+		changes = append(changes, entryChanges...)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
 
-			newFile, fileErr := os.Create(outputPath)
-			if fileErr != nil {
-				return fileErr
+	return changes, nil
+}
+
+// writeChanges writes all our changes to their fragment files, or to stdout when
+// running a dry run.
+// If any change fails to be written, the changes already written are removed.
+func (n *New) writeChanges(config *core.Config, changes []*core.Change) (err error) {
+	if n.DryRun {
+		for _, change := range changes {
+			_, err = change.WriteFormat(n.OutOrStdout(), config.FragmentExtOrDefault())
+			if err != nil {
+				return err
 			}
+		}
 
-			defer newFile.Close()
+		return nil
+	}
 
-			writer = newFile
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i], err = config.FragmentPath(n.TemplateCache, change)
+		if err != nil {
+			return err
 		}
 
-		_, err = change.WriteTo(writer)
+		// Changes created in the same second can share a name, so make sure each one is unique.
+		paths[i], err = core.UniquePath(paths[i], paths[:i])
 		if err != nil {
 			return err
 		}
 	}
 
-	return err
+	written := make([]string, 0, len(paths))
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for _, path := range written {
+			_ = os.Remove(path)
+		}
+	}()
+
+	for i, change := range changes {
+		err = os.MkdirAll(filepath.Dir(paths[i]), core.CreateDirMode)
+		if err != nil {
+			return err
+		}
+
+		err = createChangeFile(change, paths[i])
+		if err != nil {
+			return err
+		}
+
+		written = append(written, paths[i])
+	}
+
+	return nil
 }
 
 func (n *New) parsePromptEnabled() bool {
 	return n.Interactive && strings.ToLower(os.Getenv("CI")) != "true"
 }
+
+func writeChangeTo(change *core.Change, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = fragmentOf(change).WriteFormat(file, filepath.Ext(path))
+
+	return err
+}
+
+// createChangeFile writes a change to a new file, returning an error instead of
+// overwriting a file that already exists.
+func createChangeFile(change *core.Change, path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, core.CreateFileMode)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%w: %s", core.ErrFragmentExists, path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = fragmentOf(change).WriteFormat(file, filepath.Ext(path))
+
+	return err
+}
+
+// fragmentOf returns a copy of a change without the kind key and label, as those are
+// derived from the kind when a fragment is loaded and are not saved.
+func fragmentOf(change *core.Change) core.Change {
+	fragment := *change
+	fragment.KindKey = ""
+	fragment.KindLabel = ""
+
+	return fragment
+}
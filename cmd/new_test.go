@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -53,6 +54,7 @@ func TestNewWithEnvVars(t *testing.T) {
 		[]byte{106, 13},
 		[]byte("a message with testcontent"),
 		[]byte{13},
+		[]byte{13}, // confirm review
 	)
 
 	cmd := NewNew(
@@ -105,6 +107,7 @@ func TestNewWithCustomEnvVars(t *testing.T) {
 		[]byte{106, 13},
 		[]byte("a message with testcontent"),
 		[]byte{13},
+		[]byte{13}, // confirm review
 	)
 
 	cmd := NewNew(
@@ -147,6 +150,7 @@ func TestNewCreatesNewFileAfterPrompts(t *testing.T) {
 		[]byte{106, 13},
 		[]byte("a message"),
 		[]byte{13},
+		[]byte{13}, // confirm review
 	)
 
 	cmd := NewNew(
@@ -270,6 +274,7 @@ func TestNewOutputsToCmdOutWhenDry(t *testing.T) {
 		t, writer,
 		[]byte("another body"),
 		[]byte{13},
+		[]byte{13}, // confirm review
 	)
 
 	changeContent := fmt.Sprintf(
@@ -288,7 +293,7 @@ func TestNewFragmentTemplateSlash(t *testing.T) {
 	t.Setenv("CI", "false")
 
 	cfg := newTestConfig()
-	cfg.Components = []string{"test/component"}
+	cfg.Components = []core.ComponentConfig{{Label: "test/component"}}
 	then.WithTempDirConfig(t, cfg)
 	reader, writer := then.WithReadWritePipe(t)
 
@@ -298,6 +303,7 @@ func TestNewFragmentTemplateSlash(t *testing.T) {
 		[]byte{106, 13},
 		[]byte("a message"),
 		[]byte{13},
+		[]byte{13}, // confirm review
 	)
 
 	cmd := NewNew(
@@ -349,3 +355,281 @@ func TestPromptEnabled(t *testing.T) {
 		then.False(t, n.parsePromptEnabled())
 	})
 }
+
+func TestNewDetectsProjectAndComponentWithoutPrompts(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "API", Key: "api", Paths: []string{"services/api/**"}},
+		{Label: "Web", Key: "web", Paths: []string{"web/**"}},
+	}
+	cfg.Components = []core.ComponentConfig{
+		{Label: "Docs", Paths: []string{"*.md"}},
+		{Label: "Server", Paths: []string{"services/*/cmd/**"}},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	outWriter := strings.Builder{}
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.DryRun = true
+	cmd.Interactive = false
+	cmd.Kind = "added"
+	cmd.Body = "detected"
+	cmd.BaseRef = "main"
+	cmd.ChangedFiles = func(baseRef string) ([]string, error) {
+		then.Equals(t, "main", baseRef)
+		return []string{"services/api/cmd/main.go"}, nil
+	}
+	cmd.SetOut(&outWriter)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	changeContent := fmt.Sprintf(
+		"project: api\ncomponent: Server\nkind: added\nbody: detected\ntime: %s\n",
+		newMockTime().Format(time.RFC3339Nano),
+	)
+	then.Equals(t, changeContent, outWriter.String())
+}
+
+func TestNewDetectedComponentIsPreselected(t *testing.T) {
+	// we need to override this value as it would fail in CI with the interactive system
+	// but is ok here as we override stdin and stdout anyway
+	t.Setenv("CI", "false")
+
+	cfg := newTestConfig()
+	cfg.Components = []core.ComponentConfig{
+		{Label: "API", Paths: []string{"api/**"}},
+		{Label: "Web", Paths: []string{"web/**"}},
+	}
+	then.WithTempDirConfig(t, cfg)
+	reader, writer := then.WithReadWritePipe(t)
+
+	then.DelayWrite(
+		t, writer,
+		[]byte{13}, // accept the detected component
+		[]byte{13},
+		[]byte("a message"),
+		[]byte{13},
+		[]byte{13}, // confirm review
+	)
+
+	outWriter := strings.Builder{}
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.DryRun = true
+	cmd.ChangedFiles = func(string) ([]string, error) {
+		return []string{"web/index.html"}, nil
+	}
+	cmd.SetIn(reader)
+	cmd.SetOut(&outWriter)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+	then.Contains(t, "component: Web\n", outWriter.String())
+}
+
+func TestNewIgnoresDetectErrors(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Components = []core.ComponentConfig{
+		{Label: "API", Paths: []string{"api/**"}},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	// we need to override this value as it would fail in CI with the interactive system
+	// but is ok here as we override stdin and stdout anyway
+	t.Setenv("CI", "false")
+
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		[]byte{13},
+		[]byte{13},
+		[]byte("undetected"),
+		[]byte{13},
+		[]byte{13}, // confirm review
+	)
+
+	var outWriter, errWriter strings.Builder
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.DryRun = true
+	cmd.ChangedFiles = func(string) ([]string, error) {
+		return nil, errors.New("not a git repository")
+	}
+	cmd.SetIn(reader)
+	cmd.SetOut(&outWriter)
+	cmd.SetErr(&errWriter)
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+	then.Contains(t, "component: API\nkind: added\nbody: undetected", outWriter.String())
+	then.Contains(t, "not a git repository", errWriter.String())
+}
+
+func TestNewSkipsDetectWhenValuesAreProvided(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "API", Key: "api", Paths: []string{"api/**"}},
+	}
+	cfg.Components = []core.ComponentConfig{
+		{Label: "Server", Paths: []string{"api/**"}},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.DryRun = true
+	cmd.Interactive = false
+	cmd.Projects = []string{"api"}
+	cmd.Component = "Server"
+	cmd.Kind = "added"
+	cmd.Body = "provided"
+	cmd.ChangedFiles = func(string) ([]string, error) {
+		t.Fatal("changed files should not be used when values are provided")
+		return nil, nil
+	}
+	cmd.SetOut(&strings.Builder{})
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+}
+
+func TestNewFromFileCreatesAllChanges(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.FragmentFileFormat = "{{.Kind}}-{{.Custom.Issue}}"
+	cfg.CustomChoices = []core.Custom{
+		{Key: "Issue", Type: core.CustomInt},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte(`[
+  {"kind": "added", "body": "first", "custom": {"Issue": "1"}},
+  {"kind": "removed", "body": "second", "custom": {"Issue": "2"}}
+]`), "changes.json")
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.FromFile = "changes.json"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	futurePath := filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir)
+	then.DirectoryFileCount(t, 2, futurePath)
+	then.FileContents(
+		t,
+		fmt.Sprintf(
+			"kind: added\nbody: first\ntime: %s\ncustom:\n    Issue: \"1\"\n",
+			newMockTime().Format(time.RFC3339Nano),
+		),
+		futurePath, "added-1.yaml",
+	)
+	then.FileExists(t, futurePath, "removed-2.yaml")
+}
+
+func TestNewFromStdinYaml(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.FragmentFileFormat = "{{.Kind}}"
+	then.WithTempDirConfig(t, cfg)
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.FromFile = "-"
+	cmd.SetIn(strings.NewReader("- kind: added\n  body: first\n- kind: other\n  body: second\n"))
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	futurePath := filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir)
+	then.FileExists(t, futurePath, "added.yaml")
+	then.FileExists(t, futurePath, "other.yaml")
+}
+
+func TestErrorNewFromFileWritesNothingIfAnyInvalid(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte(`[
+  {"kind": "added", "body": "valid"},
+  {"kind": "missing", "body": "bad kind"},
+  {"kind": "added"}
+]`), "changes.json")
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.FromFile = "changes.json"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.NotNil(t, err)
+	then.Contains(t, "entry 1: invalid kind: missing", err.Error())
+	then.Contains(t, "entry 2: body missing and prompt is disabled", err.Error())
+	then.FileNotExists(t, cfg.ChangesDir, cfg.UnreleasedDir)
+}
+
+func TestErrorNewFromFileBadFile(t *testing.T) {
+	cfg := newTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	then.WriteFile(t, []byte(`{"not": "a list"}`), "changes.json")
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+
+	cmd.FromFile = "missing.json"
+	then.NotNil(t, cmd.Run(cmd.Command, nil))
+
+	cmd.FromFile = "changes.json"
+	then.NotNil(t, cmd.Run(cmd.Command, nil))
+}
+
+func TestNewFromFileKeepsSameSecondChangesUnique(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.FragmentFileFormat = "{{.Kind}}"
+	then.WithTempDirConfig(t, cfg)
+
+	futurePath := filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir)
+	then.WriteFile(t, []byte("kind: added\nbody: existing\n"), futurePath, "added.yaml")
+	then.WriteFile(t, []byte(`[
+  {"kind": "added", "body": "first"},
+  {"kind": "added", "body": "second"}
+]`), "changes.json")
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.FromFile = "changes.json"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	then.DirectoryFileCount(t, 3, futurePath)
+	then.FileContents(t, "kind: added\nbody: existing\n", futurePath, "added.yaml")
+
+	first, err := core.LoadChange(filepath.Join(futurePath, "added-1.yaml"))
+	then.Nil(t, err)
+	then.Equals(t, "first", first.Body)
+
+	second, err := core.LoadChange(filepath.Join(futurePath, "added-2.yaml"))
+	then.Nil(t, err)
+	then.Equals(t, "second", second.Body)
+}
+
+func TestErrorCreateChangeFileExists(t *testing.T) {
+	then.WithTempDir(t)
+	then.WriteFile(t, []byte("kind: added\n"), "change.yaml")
+
+	err := createChangeFile(&core.Change{Kind: "removed"}, "change.yaml")
+	then.Err(t, core.ErrFragmentExists, err)
+	then.FileContents(t, "kind: added\n", "change.yaml")
+}
+
+func TestNewWritesConfiguredFragmentExt(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.FragmentFileFormat = "{{.Kind}}"
+	cfg.FragmentExt = "json"
+	then.WithTempDirConfig(t, cfg)
+
+	cmd := NewNew(newMockTime, core.NewTemplateCache())
+	cmd.Interactive = false
+	cmd.Kind = "added"
+	cmd.Body = "json body"
+
+	err := cmd.Run(cmd.Command, nil)
+	then.Nil(t, err)
+
+	change, err := core.LoadChange(filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir, "added.json"))
+	then.Nil(t, err)
+	then.Equals(t, "json body", change.Body)
+}
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/miniscruff/changie/core"
 )
 
+var errExplainRequiresAuto = errors.New("explain and json are only supported with auto")
+
 type Next struct {
 	*cobra.Command
 
@@ -16,6 +23,10 @@ type Next struct {
 	Prerelease  []string
 	Meta        []string
 	Project     string
+	AllProjects bool
+	AllowMajor  bool
+	Explain     bool
+	JSON        bool
 
 	TemplateCache *core.TemplateCache
 }
@@ -32,7 +43,16 @@ func NewNext(cache *core.TemplateCache) *Next {
 Check latest version and increment part (major, minor, patch).
 If auto is used, it will try and find the next version based on what kinds of changes are
 currently unreleased.
-Echo the next release version number to be used by CI tools or other commands like batch.`,
+Echo the next release version number to be used by CI tools or other commands like batch.
+
+With --all-projects, the plan of previous and next versions of every project is
+echoed as JSON, matching the plan used by batch --all-projects.
+
+With --explain, the auto level of each change is echoed along with which changes decided
+the next version, as a table or as JSON with --json.
+
+If every unreleased change has an auto level of none, auto exits with code 3 as no release
+is needed, and the JSON output has releaseNeeded set to false.`,
 		ValidArgs: []string{"major", "minor", "patch", "auto"},
 		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 		RunE:      next.Run,
@@ -62,6 +82,34 @@ Echo the next release version number to be used by CI tools or other commands li
 		"",
 		"Specify which project we are interested in",
 	)
+	cmd.Flags().BoolVar(
+		&next.AllProjects,
+		"all-projects",
+		false,
+		"Echo the next version of every project as a JSON plan",
+	)
+	cmd.MarkFlagsMutuallyExclusive("project", "all-projects")
+	cmd.Flags().BoolVar(
+		&next.AllowMajor,
+		"allow-major",
+		false,
+		"Allow auto to bump the major version of 0.x versions when initial development is enabled",
+	)
+
+	cmd.Flags().BoolVar(
+		&next.Explain,
+		"explain",
+		false,
+		"Explain the auto level of each change and which changes decided the next version",
+	)
+	cmd.Flags().BoolVar(
+		&next.JSON,
+		"json",
+		false,
+		"Echo the next version and explanation as JSON",
+	)
+	cmd.MarkFlagsMutuallyExclusive("explain", "all-projects")
+	cmd.MarkFlagsMutuallyExclusive("json", "all-projects")
 
 	next.Command = cmd
 
@@ -78,6 +126,14 @@ func (n *Next) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if n.AllProjects {
+		return n.writePlan(cmd, config, part)
+	}
+
+	if (n.Explain || n.JSON) && part != core.AutoLevel {
+		return errExplainRequiresAuto
+	}
+
 	if len(config.Projects) > 0 {
 		var pc *core.ProjectConfig
 
@@ -87,19 +143,36 @@ func (n *Next) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		n.Project = pc.Key
+
+		// projects can override the config, such as the version extension
+		config, err = config.ForProject(pc.Key)
+		if err != nil {
+			return err
+		}
+
 		projPrefix = pc.Key + config.ProjectsVersionSeparator
 	}
 
 	var changes []core.Change
 	// only worry about loading changes, if we are in auto mode
 	if part == core.AutoLevel {
+		err = checkUnknownFiles(cmd, config, n.IncludeDirs)
+		if err != nil {
+			return err
+		}
+
 		changes, err = core.GetChanges(config, n.IncludeDirs, n.Project)
 		if err != nil {
 			return err
 		}
 	}
 
-	next, err := core.GetNextVersion(config, n.TemplateCache, part, n.Prerelease, n.Meta, changes, n.Project)
+	if n.Explain || n.JSON {
+		return n.writeExplanation(writer, config, changes, projPrefix)
+	}
+
+	next, err := core.GetNextVersion(
+		config, n.TemplateCache, part, n.AllowMajor, n.Prerelease, n.Meta, changes, n.Project)
 	if err != nil {
 		return err
 	}
@@ -108,3 +181,117 @@ func (n *Next) Run(cmd *cobra.Command, args []string) error {
 
 	return err
 }
+
+// writePlan echos the next version plan of every project as JSON.
+func (n *Next) writePlan(cmd *cobra.Command, config *core.Config, part string) error {
+	if len(config.Projects) == 0 {
+		return errProjectsNotConfigured
+	}
+
+	err := checkUnknownFiles(cmd, config, n.IncludeDirs)
+	if err != nil {
+		return err
+	}
+
+	plans, err := core.PlanProjects(
+		config, n.TemplateCache, part, n.AllowMajor, n.Prerelease, n.Meta, n.IncludeDirs)
+	if err != nil {
+		return err
+	}
+
+	bs, err := json.MarshalIndent(plans, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = cmd.OutOrStdout().Write(append(bs, '\n'))
+
+	return err
+}
+
+// writeExplanation echos the auto level of each change and the resulting next version.
+// ErrNoReleaseNeeded is returned after writing if every change has an auto level of none.
+func (n *Next) writeExplanation(
+	writer io.Writer,
+	config *core.Config,
+	changes []core.Change,
+	projPrefix string,
+) error {
+	explanation, err := core.ExplainNextVersion(
+		config, n.TemplateCache, n.AllowMajor, n.Prerelease, n.Meta, changes, n.Project)
+	if err != nil {
+		return err
+	}
+
+	if explanation.ReleaseNeeded {
+		explanation.NextVersion = projPrefix + explanation.NextVersion
+	}
+
+	if n.JSON {
+		bs, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(append(bs, '\n'))
+		if err != nil {
+			return err
+		}
+
+		if !explanation.ReleaseNeeded {
+			return core.ErrNoReleaseNeeded
+		}
+
+		return nil
+	}
+
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	_, err = fmt.Fprintln(table, "FILE\tKIND\tCOMPONENT\tLEVEL\tSOURCE\tDECISIVE")
+	if err != nil {
+		return err
+	}
+
+	for _, cl := range explanation.Changes {
+		decisive := "no"
+		if cl.Decisive {
+			decisive = "yes"
+		}
+
+		_, err = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			cl.File, cl.Kind, cl.Component, cl.Level, cl.Source, decisive)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = table.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "\nHighest level is %s\n", explanation.Level)
+	if err != nil {
+		return err
+	}
+
+	if explanation.Bump != explanation.Level {
+		_, err = fmt.Fprintf(writer, "Initial development lowers %s to %s\n", explanation.Level, explanation.Bump)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !explanation.ReleaseNeeded {
+		_, err = fmt.Fprintln(writer, "No release needed")
+		if err != nil {
+			return err
+		}
+
+		return core.ErrNoReleaseNeeded
+	}
+
+	_, err = fmt.Fprintf(writer, "Next version is %s\n", explanation.NextVersion)
+
+	return err
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -71,6 +72,50 @@ func TestNextVersionWithProject(t *testing.T) {
 	then.Equals(t, "w|v0.1.1", builder.String())
 }
 
+func TestNextVersionWithAllProjects(t *testing.T) {
+	cfg := nextTestConfig()
+	cfg.Kinds = []core.KindConfig{{Label: "added", AutoLevel: core.MinorLevel}}
+	cfg.Projects = []core.ProjectConfig{
+		{Label: "W things", Key: "w"},
+		{Label: "X things", Key: "x"},
+	}
+	then.WithTempDirConfig(t, cfg)
+
+	builder := strings.Builder{}
+	next := NewNext(core.NewTemplateCache())
+	next.AllProjects = true
+
+	next.SetOut(&builder)
+
+	then.CreateFile(t, cfg.ChangesDir, "w", "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A", Project: "w"})
+
+	err := next.Run(next.Command, []string{"auto"})
+	then.Nil(t, err)
+
+	var plans []core.ProjectPlan
+
+	err = json.Unmarshal([]byte(builder.String()), &plans)
+	then.Nil(t, err)
+	then.SliceEquals(t, []core.ProjectPlan{
+		{
+			Project:         "w",
+			Label:           "W things",
+			PreviousVersion: "v0.1.0",
+			NextVersion:     "v0.2.0",
+			Changes:         1,
+			ReleaseNeeded:   true,
+		},
+		{
+			Project:         "x",
+			Label:           "X things",
+			PreviousVersion: "v0.0.0",
+			Skipped:         true,
+			Reason:          core.SkipNoChanges,
+		},
+	}, plans)
+}
+
 func TestNextVersionWithProjectBadProject(t *testing.T) {
 	cfg := nextTestConfig()
 	cfg.ProjectsVersionSeparator = "|"
@@ -131,6 +176,37 @@ func TestNextVersionWithAuto(t *testing.T) {
 	then.Equals(t, "v0.2.0", builder.String())
 }
 
+func TestNextVersionWithAutoAllowMajor(t *testing.T) {
+	cfg := nextTestConfig()
+	cfg.InitialDevelopment = true
+	cfg.Kinds = []core.KindConfig{
+		{
+			Label:     "Breaking",
+			AutoLevel: core.MajorLevel,
+		},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.4.2.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "Breaking"})
+
+	builder := strings.Builder{}
+	next := NewNext(core.NewTemplateCache())
+	next.SetOut(&builder)
+
+	err := next.Run(next.Command, []string{"auto"})
+	then.Nil(t, err)
+	then.Equals(t, "v0.5.0", builder.String())
+
+	builder.Reset()
+	next.AllowMajor = true
+
+	err = next.Run(next.Command, []string{"auto"})
+	then.Nil(t, err)
+	then.Equals(t, "v1.0.0", builder.String())
+}
+
 func TestNextVersionWithPrereleaseAndMeta(t *testing.T) {
 	cfg := nextTestConfig()
 	then.WithTempDirConfig(t, cfg)
@@ -206,3 +282,111 @@ func TestErrorNextUnableToGetChanges(t *testing.T) {
 	err := next.Run(next.Command, []string{"auto"})
 	then.NotNil(t, err)
 }
+
+func TestNextVersionWithExplain(t *testing.T) {
+	cfg := nextTestConfig()
+	cfg.InitialDevelopment = true
+	cfg.Kinds = []core.KindConfig{
+		{Label: "Feature", AutoLevel: core.MinorLevel},
+		{Label: "Fix", AutoLevel: core.PatchLevel},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "Feature", Filename: "chgs/unrel/a.yaml"})
+	writeChangeFile(t, cfg, &core.Change{Kind: "Fix", Filename: "chgs/unrel/b.yaml"})
+
+	builder := strings.Builder{}
+	next := NewNext(core.NewTemplateCache())
+	next.SetOut(&builder)
+	next.Explain = true
+
+	err := next.Run(next.Command, []string{"auto"})
+	then.Nil(t, err)
+	then.Equals(t, `FILE               KIND     COMPONENT  LEVEL  SOURCE  DECISIVE
+chgs/unrel/a.yaml  Feature             minor  kind    yes
+chgs/unrel/b.yaml  Fix                 patch  kind    no
+
+Highest level is minor
+Initial development lowers minor to patch
+Next version is v0.1.1
+`, builder.String())
+}
+
+func TestNextVersionWithExplainAsJSON(t *testing.T) {
+	cfg := nextTestConfig()
+	cfg.Kinds = []core.KindConfig{
+		{Label: "Feature", AutoLevel: core.MinorLevel},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "Feature", Filename: "chgs/unrel/a.yaml"})
+
+	builder := strings.Builder{}
+	next := NewNext(core.NewTemplateCache())
+	next.SetOut(&builder)
+	next.Explain = true
+	next.JSON = true
+
+	err := next.Run(next.Command, []string{"auto"})
+	then.Nil(t, err)
+
+	var explanation core.AutoLevelExplanation
+
+	then.Nil(t, json.Unmarshal([]byte(builder.String()), &explanation))
+	then.Equals(t, core.MinorLevel, explanation.Level)
+	then.Equals(t, core.MinorLevel, explanation.Bump)
+	then.Equals(t, "v0.1.0", explanation.PreviousVersion)
+	then.Equals(t, "v0.2.0", explanation.NextVersion)
+	then.SliceEquals(t, []core.ChangeLevel{
+		{File: "chgs/unrel/a.yaml", Kind: "Feature", Level: core.MinorLevel, Source: core.LevelFromKind, Decisive: true},
+	}, explanation.Changes)
+}
+
+func TestErrorNextExplainRequiresAuto(t *testing.T) {
+	cfg := nextTestConfig()
+	then.WithTempDirConfig(t, cfg)
+
+	next := NewNext(core.NewTemplateCache())
+	next.Explain = true
+
+	err := next.Run(next.Command, []string{"patch"})
+	then.Err(t, errExplainRequiresAuto, err)
+}
+
+func TestNextVersionWithAutoNoReleaseNeeded(t *testing.T) {
+	cfg := nextTestConfig()
+	cfg.Kinds = []core.KindConfig{
+		{Label: "Internal", AutoLevel: core.NoneLevel},
+	}
+
+	then.WithTempDirConfig(t, cfg)
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.1.0.md")
+	writeChangeFile(t, cfg, &core.Change{Kind: "Internal", Filename: "chgs/unrel/a.yaml"})
+
+	builder := strings.Builder{}
+	next := NewNext(core.NewTemplateCache())
+	next.SetOut(&builder)
+
+	err := next.Run(next.Command, []string{"auto"})
+	then.Err(t, core.ErrNoReleaseNeeded, err)
+	then.Equals(t, ExitNoReleaseNeeded, ExitCode(err))
+	then.Equals(t, "", builder.String())
+
+	next.JSON = true
+
+	err = next.Run(next.Command, []string{"auto"})
+	then.Err(t, core.ErrNoReleaseNeeded, err)
+
+	var explanation core.AutoLevelExplanation
+
+	then.Nil(t, json.Unmarshal([]byte(builder.String()), &explanation))
+	then.False(t, explanation.ReleaseNeeded)
+	then.Equals(t, core.NoneLevel, explanation.Level)
+	then.Equals(t, "v0.1.0", explanation.PreviousVersion)
+	then.Equals(t, "", explanation.NextVersion)
+}
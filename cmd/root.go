@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -8,6 +9,18 @@ import (
 	"github.com/miniscruff/changie/core"
 )
 
+// ExitNoReleaseNeeded is the exit code used when every unreleased change has an auto level of none.
+const ExitNoReleaseNeeded = 3
+
+// ExitCode returns the exit code to use for an error returned by a command.
+func ExitCode(err error) int {
+	if errors.Is(err, core.ErrNoReleaseNeeded) {
+		return ExitNoReleaseNeeded
+	}
+
+	return 1
+}
+
 // rootCmd represents the base command when called without any subcommands
 func RootCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -23,7 +36,7 @@ being easy to use for developers and your release team.`,
 	templateCache := core.NewTemplateCache()
 
 	batch := NewBatch(time.Now, templateCache)
-	merge := NewMerge(templateCache)
+	merge := NewMerge(time.Now, templateCache)
 
 	cmd.AddCommand(batch.Command)
 	cmd.AddCommand(NewGen().Command)
@@ -33,6 +46,12 @@ being easy to use for developers and your release team.`,
 	cmd.AddCommand(NewNew(time.Now, templateCache).Command)
 	cmd.AddCommand(NewNext(templateCache).Command)
 	cmd.AddCommand(NewDiff().Command)
+	cmd.AddCommand(NewDoctor().Command)
+	cmd.AddCommand(NewEdit(templateCache).Command)
+	cmd.AddCommand(NewFind().Command)
+	cmd.AddCommand(NewFragments(templateCache).Command)
+	cmd.AddCommand(NewMigrateKinds().Command)
+	cmd.AddCommand(NewVersions().Command)
 
 	return cmd
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miniscruff/changie/core"
+)
+
+type Versions struct {
+	*cobra.Command
+
+	// CLI args
+	SkipPrereleases bool
+	Project         string
+	JSON            bool
+}
+
+func NewVersions() *Versions {
+	v := &Versions{}
+
+	cmd := &cobra.Command{
+		Use:   "versions",
+		Short: "List released versions with release dates and change counts",
+		Long: `List every released version, newest first, with when it was released and
+the number of changes it included.
+
+The release date and change count are read from the version frontmatter if available,
+otherwise the date of the commit that added the version file is used and the count is unknown.`,
+		Args: cobra.NoArgs,
+		RunE: v.Run,
+	}
+	cmd.Flags().BoolVar(
+		&v.SkipPrereleases,
+		"skip-prereleases",
+		false,
+		"Excludes prereleases from the list",
+	)
+	cmd.Flags().StringVarP(
+		&v.Project,
+		"project", "j",
+		"",
+		"Specify which project we are interested in",
+	)
+	cmd.Flags().BoolVar(
+		&v.JSON,
+		"json",
+		false,
+		"Echo the versions as JSON",
+	)
+
+	v.Command = cmd
+
+	return v
+}
+
+func (v *Versions) Run(cmd *cobra.Command, args []string) error {
+	config, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(config.Projects) > 0 {
+		var pc *core.ProjectConfig
+
+		pc, err = config.Project(v.Project)
+		if err != nil {
+			return err
+		}
+
+		v.Project = pc.Key
+
+		// projects can override the config, such as the version extension
+		config, err = config.ForProject(pc.Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	releases, err := core.GetReleasedVersions(config, v.SkipPrereleases, v.Project)
+	if err != nil {
+		return err
+	}
+
+	if v.JSON {
+		bs, err := json.MarshalIndent(releases, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = cmd.OutOrStdout().Write(append(bs, '\n'))
+
+		return err
+	}
+
+	table := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+
+	_, err = fmt.Fprintln(table, "VERSION\tRELEASED\tCHANGES")
+	if err != nil {
+		return err
+	}
+
+	for _, release := range releases {
+		changes := "-"
+		if release.Metadata != nil {
+			changes = strconv.Itoa(release.Metadata.Changes)
+		}
+
+		_, err = fmt.Fprintf(table, "%s\t%s\t%s\n", release.Version, release.Time.Format(time.DateOnly), changes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return table.Flush()
+}
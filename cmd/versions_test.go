@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miniscruff/changie/core"
+	"github.com/miniscruff/changie/then"
+)
+
+func TestVersionsListsReleases(t *testing.T) {
+	cfg := batchTestConfig()
+	cfg.VersionFrontmatter = true
+	then.WithTempDirConfig(t, cfg)
+
+	released := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	timeNow := func() time.Time {
+		return released
+	}
+
+	// older version files without frontmatter use the modified time
+	then.WriteFile(t, []byte("## v0.1.0\n"), cfg.ChangesDir, "v0.1.0.md")
+	old := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
+	then.Nil(t, os.Chtimes(filepath.Join(cfg.ChangesDir, "v0.1.0.md"), old, old))
+
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "A"})
+	writeChangeFile(t, cfg, &core.Change{Kind: "added", Body: "B"})
+	writeChangeFile(t, cfg, &core.Change{Kind: "removed", Body: "C"})
+
+	batch := NewBatch(timeNow, core.NewTemplateCache())
+	err := batch.Run(batch.Command, []string{"v0.2.0"})
+	then.Nil(t, err)
+
+	builder := strings.Builder{}
+	versions := NewVersions()
+	versions.SetOut(&builder)
+
+	err = versions.Run(versions.Command, nil)
+	then.Nil(t, err)
+	then.Equals(t, `VERSION  RELEASED    CHANGES
+v0.2.0   2026-03-04  3
+v0.1.0   2025-11-02  -
+`, builder.String())
+
+	builder.Reset()
+	versions.JSON = true
+
+	err = versions.Run(versions.Command, nil)
+	then.Nil(t, err)
+
+	var releases []core.ReleasedVersion
+
+	then.Nil(t, json.Unmarshal([]byte(builder.String()), &releases))
+	then.SliceLen(t, 2, releases)
+	then.Equals(t, "v0.2.0", releases[0].Version)
+	then.True(t, released.Equal(releases[0].Time))
+	then.Equals(t, "v0.1.0", releases[0].Metadata.PreviousVersion)
+	then.MapEquals(t, map[string]int{"added": 2, "removed": 1}, releases[0].Metadata.Kinds)
+	then.Equals(t, nil, releases[1].Metadata)
+}
@@ -1,15 +1,25 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/pelletier/go-toml/v2"
 	"gopkg.in/yaml.v3"
 )
 
+// FragmentExts are the supported change fragment file extensions.
+var FragmentExts = []string{"yaml", "yml", "json", "toml"}
+
+var ErrUnknownFragmentExt = errors.New("fragment extension must be one of yaml, yml, json or toml")
+
 // Less will compare two Change values with the config settings.
 // * Components, if enabled and componentFormat is configured, are sorted by index in config
 // * Kind, if enabled and kindFormat is configured, are sorted by index in config
@@ -22,9 +32,9 @@ func ChangeLess(cfg *Config, changes []Change) func(i, j int) bool {
 		// Start by sorting by component index
 		if cfg.ComponentFormat != "" && len(cfg.Components) > 0 && a.Component != b.Component {
 			for _, c := range cfg.Components {
-				if a.Component == c {
+				if a.Component == c.KeyOrLabel() {
 					return true
-				} else if b.Component == c {
+				} else if b.Component == c.KeyOrLabel() {
 					return false
 				}
 			}
@@ -49,16 +59,19 @@ func ChangeLess(cfg *Config, changes []Change) func(i, j int) bool {
 // Change represents an atomic change to a project.
 type Change struct {
 	// Project of our change, if one was provided.
-	Project string `yaml:",omitempty" default:""`
+	Project string `yaml:",omitempty" json:"project,omitempty" toml:"project,omitempty" default:""`
+	// Projects of our change, if the change is shared by multiple projects.
+	// See [sharedProjectFragments](#config-sharedprojectfragments) for configuration.
+	Projects []string `yaml:",omitempty" json:"projects,omitempty" toml:"projects,omitempty" default:"nil"`
 	// Component of our change, if one was provided.
-	Component string `yaml:",omitempty" default:""`
+	Component string `yaml:",omitempty" json:"component,omitempty" toml:"component,omitempty" default:""`
 	// Kind key of our change, if one was provided.
 	// Backwards compatible alias for KindKey.
-	Kind string `yaml:",omitempty" default:""`
+	Kind string `yaml:",omitempty" json:"kind,omitempty" toml:"kind,omitempty" default:""`
 	// Body message of our change, if one was provided.
-	Body string `yaml:",omitempty" default:""`
+	Body string `yaml:",omitempty" json:"body,omitempty" toml:"body,omitempty" default:""`
 	// When our change was made.
-	Time time.Time `yaml:"" required:"true"`
+	Time time.Time `yaml:"" json:"time" toml:"time" required:"true"`
 	// Custom values corresponding to our options where each key-value pair is the key of the custom option
 	// and value the one provided in the change.
 	// example: yaml
@@ -66,19 +79,19 @@ type Change struct {
 	// - key: Issue
 	//   type: int
 	// changeFormat: "{{.Body}} from #{{.Custom.Issue}}"
-	Custom map[string]string `yaml:",omitempty" default:"nil"`
+	Custom map[string]string `yaml:",omitempty" json:"custom,omitempty" toml:"custom,omitempty" default:"nil"`
 	// Env vars configured by the system.
 	// This is not written in change fragments but instead loaded by the system and accessible for templates.
 	// For example if you want to use an env var in [change format](#config-changeformat) you can,
 	// but env vars configured when executing `changie new` will not be saved.
 	// See [envPrefix](#config-envprefix) for configuration.
-	Env map[string]string `yaml:"-" default:"nil"`
+	Env map[string]string `yaml:"-" json:"-" toml:"-" default:"nil"`
 	// Filename the change was saved to.
-	Filename string `yaml:"-"`
+	Filename string `yaml:"-" json:"-" toml:"-"`
 	// Kind key of our change, if one was provided.
-	KindKey string `yaml:"kindKey,omitempty" default:""`
+	KindKey string `yaml:"kindKey,omitempty" json:"kindKey,omitempty" toml:"kindKey,omitempty" default:""`
 	// Kind label of our change, if one was provided.
-	KindLabel string `yaml:"kindLabel,omitempty" default:""`
+	KindLabel string `yaml:"kindLabel,omitempty" json:"kindLabel,omitempty" toml:"kindLabel,omitempty" default:""`
 }
 
 // WriteTo will write a change to the writer as YAML
@@ -89,7 +102,36 @@ func (change Change) WriteTo(writer io.Writer) (int64, error) {
 	return int64(n), err
 }
 
-func (change *Change) PostProcess(cfg *Config, kind *KindConfig) error {
+// WriteFormat will write a change to the writer in the format of a fragment extension,
+// one of yaml, yml, json or toml.
+func (change Change) WriteFormat(writer io.Writer, ext string) (int64, error) {
+	var (
+		bs  []byte
+		err error
+	)
+
+	switch strings.TrimPrefix(ext, ".") {
+	case "yaml", "yml":
+		return change.WriteTo(writer)
+	case "json":
+		bs, err = json.MarshalIndent(&change, "", "  ")
+		bs = append(bs, '\n')
+	case "toml":
+		bs, err = toml.Marshal(&change)
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownFragmentExt, ext)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := writer.Write(bs)
+
+	return int64(n), err
+}
+
+func (change *Change) PostProcess(cfg *Config, kind *KindConfig, component *ComponentConfig) error {
 	postConfigs := make([]PostProcessConfig, 0)
 
 	if kind == nil || !kind.SkipGlobalPost {
@@ -100,10 +142,18 @@ func (change *Change) PostProcess(cfg *Config, kind *KindConfig) error {
 		postConfigs = append(postConfigs, kind.Post...)
 	}
 
+	if component != nil {
+		postConfigs = append(postConfigs, component.Post...)
+	}
+
 	if len(postConfigs) == 0 {
 		return nil
 	}
 
+	if change.Custom == nil {
+		change.Custom = make(map[string]string)
+	}
+
 	templateCache := NewTemplateCache()
 
 	for _, post := range postConfigs {
@@ -120,6 +170,21 @@ func (change *Change) PostProcess(cfg *Config, kind *KindConfig) error {
 	return nil
 }
 
+// HasProject returns whether the change is part of a project, either as the
+// project or one of the shared projects.
+func (change Change) HasProject(projectKey string) bool {
+	return change.Project == projectKey || slices.Contains(change.Projects, projectKey)
+}
+
+// firstProject returns the project of the change, or the first shared project.
+func (change Change) firstProject() string {
+	if change.Project != "" || len(change.Projects) == 0 {
+		return change.Project
+	}
+
+	return change.Projects[0]
+}
+
 // LoadChange will load a change from file path
 func LoadChange(path string) (Change, error) {
 	var c Change
@@ -129,7 +194,15 @@ func LoadChange(path string) (Change, error) {
 		return c, fmt.Errorf("reading change file '%s': %w", path, err)
 	}
 
-	err = yaml.Unmarshal(bs, &c)
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(bs, &c)
+	case ".toml":
+		err = toml.Unmarshal(bs, &c)
+	default:
+		err = yaml.Unmarshal(bs, &c)
+	}
+
 	if err != nil {
 		return c, fmt.Errorf("unmarshaling change file '%s': %w", path, err)
 	}
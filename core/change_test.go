@@ -66,6 +66,71 @@ func TestBadYamlFile(t *testing.T) {
 	then.NotNil(t, err)
 }
 
+func TestLoadChangeFromOtherFormats(t *testing.T) {
+	then.WithTempDir(t)
+
+	for name, contents := range map[string]string{
+		"change.yml":  "kind: A\nbody: hey\n",
+		"change.json": `{"kind": "A", "body": "hey"}`,
+		"change.toml": "kind = 'A'\nbody = 'hey'\n",
+	} {
+		then.Nil(t, os.WriteFile(name, []byte(contents), CreateFileMode))
+
+		change, err := LoadChange(name)
+
+		then.Nil(t, err)
+		then.Equals(t, "A", change.Kind)
+		then.Equals(t, "hey", change.Body)
+		then.Equals(t, name, change.Filename)
+	}
+}
+
+func TestWriteChangeFormatsCanBeLoaded(t *testing.T) {
+	then.WithTempDir(t)
+
+	change := Change{
+		Kind:   "A",
+		Body:   "some body message",
+		Time:   time.Date(2016, 5, 24, 3, 30, 10, 5, time.UTC),
+		Custom: map[string]string{"Issue": "15"},
+	}
+
+	for _, ext := range FragmentExts {
+		var builder strings.Builder
+
+		_, err := change.WriteFormat(&builder, "."+ext)
+		then.Nil(t, err)
+
+		name := "change." + ext
+		then.Nil(t, os.WriteFile(name, []byte(builder.String()), CreateFileMode))
+
+		loaded, err := LoadChange(name)
+		then.Nil(t, err)
+		then.Equals(t, change.Body, loaded.Body)
+		then.Equals(t, change.Kind, loaded.Kind)
+		then.True(t, change.Time.Equal(loaded.Time))
+		then.MapEquals(t, change.Custom, loaded.Custom)
+	}
+}
+
+func TestErrorWriteChangeUnknownFormat(t *testing.T) {
+	var builder strings.Builder
+
+	_, err := Change{}.WriteFormat(&builder, "xml")
+	then.Err(t, ErrUnknownFragmentExt, err)
+}
+
+func TestBadJsonAndTomlFiles(t *testing.T) {
+	then.WithTempDir(t)
+
+	for _, name := range []string{"some_file.json", "some_file.toml"} {
+		then.Nil(t, os.WriteFile(name, []byte("not a valid file---"), CreateFileMode))
+
+		_, err := LoadChange(name)
+		then.NotNil(t, err)
+	}
+}
+
 func TestSortByTime(t *testing.T) {
 	changes := []Change{
 		{Body: "third", Time: orderedTimes[2]},
@@ -128,7 +193,7 @@ func TestSortByComponentThenKind(t *testing.T) {
 			{Label: "D"},
 			{Label: "E"},
 		},
-		Components:      []string{"A", "B", "C"},
+		Components:      []ComponentConfig{{Label: "A"}, {Label: "B"}, {Label: "C"}},
 		ComponentFormat: "## {{.Component}}",
 		KindFormat:      "* {{.Kind}}",
 	}
@@ -152,7 +217,7 @@ func TestSortByKindWhenComponentFormatIsEmpty(t *testing.T) {
 			{Label: "D"},
 			{Label: "E"},
 		},
-		Components: []string{"A", "B", "C"},
+		Components: []ComponentConfig{{Label: "A"}, {Label: "B"}, {Label: "C"}},
 		KindFormat: "* {{.Kind}}",
 	}
 	changes := []Change{
@@ -170,6 +235,13 @@ func TestSortByKindWhenComponentFormatIsEmpty(t *testing.T) {
 	then.Equals(t, "fourth", changes[3].Body)
 }
 
+func TestChangeHasProject(t *testing.T) {
+	then.True(t, Change{Project: "cli"}.HasProject("cli"))
+	then.True(t, Change{Projects: []string{"cli", "sdk"}}.HasProject("sdk"))
+	then.False(t, Change{Projects: []string{"cli"}}.HasProject("docs"))
+	then.False(t, Change{}.HasProject("cli"))
+}
+
 func TestLoadEnvVars(t *testing.T) {
 	config := Config{
 		EnvPrefix: "TEST_CHANGIE_",
@@ -193,7 +265,7 @@ func TestPostProcess_NoPostConfigs(t *testing.T) {
 	cfg := &Config{}
 	change := &Change{}
 
-	then.Nil(t, change.PostProcess(cfg, nil))
+	then.Nil(t, change.PostProcess(cfg, nil, nil))
 	then.MapLen(t, 0, change.Custom)
 }
 
@@ -210,7 +282,7 @@ func TestPostProcess_NilKindGlobalPost(t *testing.T) {
 		Custom: make(map[string]string),
 	}
 
-	then.Nil(t, change.PostProcess(cfg, nil))
+	then.Nil(t, change.PostProcess(cfg, nil, nil))
 	then.MapLen(t, 1, change.Custom)
 	then.Equals(t, "Yes", change.Custom["NilKind"])
 }
@@ -229,11 +301,28 @@ func TestPostProcess_KindPost(t *testing.T) {
 		},
 	}
 
-	then.Nil(t, change.PostProcess(cfg, kindCfg))
+	then.Nil(t, change.PostProcess(cfg, kindCfg, nil))
 	then.MapLen(t, 1, change.Custom)
 	then.Equals(t, "AlsoYes", change.Custom["NotNilKind"])
 }
 
+func TestPostProcess_ComponentPost(t *testing.T) {
+	cfg := &Config{}
+	change := &Change{}
+	componentCfg := &ComponentConfig{
+		Post: []PostProcessConfig{
+			{
+				Key:   "Team",
+				Value: "Platform",
+			},
+		},
+	}
+
+	then.Nil(t, change.PostProcess(cfg, nil, componentCfg))
+	then.MapLen(t, 1, change.Custom)
+	then.Equals(t, "Platform", change.Custom["Team"])
+}
+
 func TestPostProcess_InvalidPostTemplate(t *testing.T) {
 	cfg := &Config{}
 	change := &Change{
@@ -248,5 +337,5 @@ func TestPostProcess_InvalidPostTemplate(t *testing.T) {
 		},
 	}
 
-	then.NotNil(t, change.PostProcess(cfg, kindCfg))
+	then.NotNil(t, change.PostProcess(cfg, kindCfg, nil))
 }
@@ -6,6 +6,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"gopkg.in/yaml.v3"
@@ -24,6 +27,14 @@ const (
 	PatchLevel = "patch"
 	NoneLevel  = "none"
 	EmptyLevel = ""
+
+	VersionSourceFiles = "files"
+	VersionSourceTags  = "tags"
+
+	VersionOrderDescending     = "descending"
+	VersionOrderAscending      = "ascending"
+	VersionOrderDateDescending = "date-descending"
+	VersionOrderDateAscending  = "date-ascending"
 )
 
 var ConfigPaths []string = []string{
@@ -34,6 +45,12 @@ var ConfigPaths []string = []string{
 var (
 	ErrConfigNotFound   = errors.New("no changie config found")
 	ErrInvalidAutoLevel = errors.New("auto level must resolve to major, minor, patch or none")
+	ErrVersionSource    = errors.New("version source must be files or tags")
+	ErrVersionOrder     = errors.New(
+		"version order must be descending, ascending, date-descending or date-ascending",
+	)
+
+	ErrFragmentOutsideUnreleased = errors.New("fragment path must be inside the unreleased directory")
 )
 
 // GetVersions will return, in semver sorted order, all released versions
@@ -50,6 +67,13 @@ type KindConfig struct {
 	// example: yaml
 	// label: Feature
 	Label string `yaml:"label,omitempty" required:"true"`
+	// Aliases are previous keys or labels of this kind.
+	// Changes using an alias are treated as this kind, which allows renaming a kind
+	// without breaking existing change fragments.
+	// Use `changie migrate-kinds` to rewrite existing fragments with the new kind.
+	// example: yaml
+	// aliases: [Feature, Features]
+	Aliases []string `yaml:"aliases,omitempty"`
 	// Format will override the root kind format when building the kind header.
 	// example: yaml
 	// format: '### {{.Kind}} **Breaking Changes**'
@@ -93,11 +117,20 @@ func (kc *KindConfig) KeyOrLabel() string {
 // The auto value supports go templates using the change as the template data,
 // so the level can be derived from custom values.
 func (kc *KindConfig) AutoLevelForChange(cache *TemplateCache, change Change) (string, error) {
-	if kc.AutoLevel == "" {
+	level, err := autoLevelForChange(cache, kc.AutoLevel, change)
+	if errors.Is(err, ErrInvalidAutoLevel) {
+		return EmptyLevel, fmt.Errorf("kind %q wit auto config %q: %w", kc.KeyOrLabel(), kc.AutoLevel, err)
+	}
+
+	return level, err
+}
+
+func autoLevelForChange(cache *TemplateCache, autoLevel string, change Change) (string, error) {
+	if autoLevel == "" {
 		return EmptyLevel, ErrMissingAutoLevel
 	}
 
-	level, err := cache.ExecuteString(kc.AutoLevel, change)
+	level, err := cache.ExecuteString(autoLevel, change)
 	if err != nil {
 		return EmptyLevel, err
 	}
@@ -106,8 +139,104 @@ func (kc *KindConfig) AutoLevelForChange(cache *TemplateCache, change Change) (s
 	case MajorLevel, MinorLevel, PatchLevel, NoneLevel:
 		return level, nil
 	default:
-		return EmptyLevel, fmt.Errorf("kind %q wit auto config %q: %w", kc.KeyOrLabel(), kc.AutoLevel, ErrInvalidAutoLevel)
+		return EmptyLevel, ErrInvalidAutoLevel
+	}
+}
+
+// Component config allows you to customize the options depending on what component was selected.
+// Components can also be configured as a plain string, which is used as the label.
+type ComponentConfig struct {
+	// Key is the value used for lookups and file names for components.
+	// By default it will use label if no key is provided.
+	// example: yaml
+	// key: api
+	Key string `yaml:"key,omitempty"`
+	// Label is the value used in the prompt and component header.
+	// example: yaml
+	// label: API
+	Label string `yaml:"label,omitempty" required:"true"`
+	// Description is shown next to the label when selecting a component.
+	// example: yaml
+	// description: Public REST and gRPC endpoints
+	Description string `yaml:"description,omitempty"`
+	// Aliases are previous keys or labels of this component.
+	// Changes using an alias are treated as this component.
+	// example: yaml
+	// aliases: [backend]
+	Aliases []string `yaml:"aliases,omitempty"`
+	// Format will override the root component format when building the component header.
+	// example: yaml
+	// format: '## {{.Component}} **Internal**'
+	Format string `yaml:"format,omitempty" templateType:"ComponentData"`
+	// Additional choices allows adding choices per component
+	AdditionalChoices []Custom `yaml:"additionalChoices,omitempty"`
+	// Post process options when saving a new change fragment specific to this component.
+	Post []PostProcessConfig `yaml:"post,omitempty"`
+	// Auto overrides the kind auto level when using `batch auto` or `next auto`
+	// for changes of this component.
+	// Possible values are major, minor, patch or none and supports go templates
+	// the same as the kind auto level.
+	// example: yaml
+	// auto: patch
+	AutoLevel string `yaml:"auto,omitempty" templateType:"Change"`
+	// Paths are file path patterns used to detect this component from changed files
+	// when creating a new change.
+	// See [project paths](#projectconfig-paths) for the pattern syntax.
+	// example: yaml
+	// paths: ["api/**", "proto/*.proto"]
+	Paths []string `yaml:"paths,omitempty"`
+}
+
+// KeyOrLabel returns the component config key if set, otherwise the label
+func (cc *ComponentConfig) KeyOrLabel() string {
+	if cc.Key != "" {
+		return cc.Key
+	}
+
+	return cc.Label
+}
+
+// AutoLevelForChange resolves the auto bump level override for the provided change.
+func (cc *ComponentConfig) AutoLevelForChange(cache *TemplateCache, change Change) (string, error) {
+	level, err := autoLevelForChange(cache, cc.AutoLevel, change)
+	if errors.Is(err, ErrInvalidAutoLevel) {
+		return EmptyLevel, fmt.Errorf("component %q with auto config %q: %w", cc.KeyOrLabel(), cc.AutoLevel, err)
+	}
+
+	return level, err
+}
+
+// UnmarshalYAML allows components to be configured as a plain string label or an object.
+func (cc *ComponentConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*cc = ComponentConfig{Label: value.Value}
+		return nil
+	}
+
+	// use a different type to avoid recursively calling this method
+	type rawComponentConfig ComponentConfig
+
+	var raw rawComponentConfig
+
+	err := value.Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	*cc = ComponentConfig(raw)
+
+	return nil
+}
+
+// MarshalYAML writes components with only a label as a plain string.
+func (cc ComponentConfig) MarshalYAML() (any, error) {
+	if reflect.DeepEqual(cc, ComponentConfig{Label: cc.Label}) {
+		return cc.Label, nil
 	}
+
+	type rawComponentConfig ComponentConfig
+
+	return rawComponentConfig(cc), nil
 }
 
 // Body config allows you to customize the default body prompt
@@ -225,6 +354,20 @@ type ProjectConfig struct {
 	// example: yaml
 	// changelog: src/frontend/CHANGELOG.md
 	ChangelogPath string `yaml:"changelog"`
+	// Aliases are previous keys or labels of this project.
+	// Changes using an alias are treated as part of this project.
+	// example: yaml
+	// aliases: [web]
+	Aliases []string `yaml:"aliases,omitempty"`
+	// Paths are file path patterns used to detect this project from changed files
+	// when creating a new change.
+	// Patterns are relative to where changie is run and use
+	// [path.Match](https://pkg.go.dev/path#Match) syntax, with a trailing `/**` matching
+	// every file in a directory.
+	// Matching projects are pre-selected in the prompt, or used when prompts are disabled.
+	// example: yaml
+	// paths: ["src/frontend/**"]
+	Paths []string `yaml:"paths,omitempty"`
 	// Replacements to run when merging a changelog for our project.
 	// example: yaml
 	// # nodejs package.json replacement
@@ -233,6 +376,84 @@ type ProjectConfig struct {
 	//   find: '  "version": ".*",'
 	//   replace: '  "version": "{{.VersionNoPrefix}}",'
 	Replacements []Replacement `yaml:"replacements"`
+	// Kinds overrides the root kinds for this project.
+	// example: yaml
+	// kinds:
+	//   - label: Command
+	//   - label: Fixed
+	Kinds []KindConfig `yaml:"kinds,omitempty"`
+	// Components overrides the root components for this project.
+	Components []ComponentConfig `yaml:"components,omitempty"`
+	// Custom choices overrides the root custom choices for this project.
+	CustomChoices []Custom `yaml:"custom,omitempty"`
+	// Version format overrides the root version format for this project.
+	VersionFormat string `yaml:"versionFormat,omitempty" templateType:"BatchData"`
+	// Component format overrides the root component format for this project.
+	ComponentFormat string `yaml:"componentFormat,omitempty" templateType:"ComponentData"`
+	// Kind format overrides the root kind format for this project.
+	KindFormat string `yaml:"kindFormat,omitempty" templateType:"KindData"`
+	// Change format overrides the root change format for this project.
+	ChangeFormat string `yaml:"changeFormat,omitempty" templateType:"Change"`
+	// Header format overrides the root header format for this project.
+	HeaderFormat string `yaml:"headerFormat,omitempty" templateType:"BatchData"`
+	// Footer format overrides the root footer format for this project.
+	FooterFormat string `yaml:"footerFormat,omitempty" templateType:"BatchData"`
+	// Newlines overrides all the root newline options for this project.
+	Newlines *NewlinesConfig `yaml:"newlines,omitempty"`
+	// Header path overrides the root changelog header file for this project,
+	// relative to the changes directory.
+	HeaderPath string `yaml:"headerPath,omitempty"`
+	// Version ext overrides the root version file extension for this project.
+	VersionExt string `yaml:"versionExt,omitempty"`
+}
+
+// Changelog archive moves older versions out of the merged changelog into archive files,
+// one per major version, linked from the end of the changelog.
+// Versions are kept in the changelog if they match every keep option.
+// example: yaml
+// keepVersions: 20
+// keepCurrentMajor: true
+// pathFormat: "CHANGELOG-{{.Major}}.x.md"
+// linkFormat: "* [Version {{.Major}}.x]({{.Path}})"
+type ChangelogArchiveConfig struct {
+	// Number of the latest versions to keep in the changelog, 0 keeps every version.
+	KeepVersions int `yaml:"keepVersions,omitempty"`
+	// Keep only versions with the same major as the latest version in the changelog.
+	KeepCurrentMajor bool `yaml:"keepCurrentMajor,omitempty"`
+	// Template for the file name of each archive, relative to the changelog.
+	PathFormat string `yaml:"pathFormat,omitempty" default:"CHANGELOG-{{.Major}}.x.md" templateType:"ArchiveData"`
+	// Template for the link to each archive written at the end of the changelog.
+	LinkFormat string `yaml:"linkFormat,omitempty" default:"* [{{.Major}}.x]({{.Path}})" templateType:"ArchiveData"`
+}
+
+// Combined changelog configures a single changelog including the releases of all projects.
+// Releases are ordered newest first, using the date of the git commit that added each
+// version file, and releases of the same time keep the order of projects in the config.
+// Version files that are not committed yet use their modification time.
+// example: yaml
+// path: CHANGELOG.md
+// headerPath: header.tpl.md
+// releaseFormat: "## {{.Project}} {{.Version}}"
+type CombinedChangelogConfig struct {
+	// Filepath for the combined changelog.
+	// Relative to project root.
+	// example: yaml
+	// path: CHANGELOG.md
+	Path string `yaml:"path" required:"true"`
+	// Filepath for the header of the combined changelog.
+	// Relative to [changesDir](#config-changesdir).
+	HeaderPath string `yaml:"headerPath,omitempty"`
+	// Template used to group releases by date.
+	// A date header is written whenever the result changes between releases,
+	// so the grouping follows the precision of the template.
+	// If empty, releases are interleaved without date headers.
+	// example: yaml
+	// dateFormat: '## {{.Time.Format "January 2006"}}'
+	DateFormat string `yaml:"dateFormat,omitempty" templateType:"CombinedReleaseData"`
+	// Template written before each release to include the project in the heading.
+	// example: yaml
+	// releaseFormat: "### {{.Project}} {{.Version}}"
+	ReleaseFormat string `yaml:"releaseFormat,omitempty" templateType:"CombinedReleaseData"`
 }
 
 // Config handles configuration for a project.
@@ -280,6 +501,36 @@ type Config struct {
 	// example: yaml
 	// changelogPath: CHANGELOG.md
 	ChangelogPath string `yaml:"changelogPath,omitempty"`
+	// Template written after the header file when merging, such as a table of contents.
+	// Receives every version in the changelog.
+	// example: yaml
+	// changelogHeaderFormat: |
+	//   {{ range .Versions -}}
+	//   * [{{.Version}}](#{{.Anchor}})
+	//   {{ end -}}
+	ChangelogHeaderFormat string `yaml:"changelogHeaderFormat,omitempty" templateType:"ChangelogData"`
+	// Changelog version anchors writes an explicit anchor before each version when merging,
+	// such as `<a name="v120"></a>` for v1.2.0.
+	// Links using the anchor keep working if the version heading changes.
+	// example: yaml
+	// changelogVersionAnchors: true
+	ChangelogVersionAnchors bool `yaml:"changelogVersionAnchors,omitempty"`
+	// Template written at the end of the changelog when merging, such as compare links.
+	// Receives every version in the changelog along with the previous and next versions.
+	// example: yaml
+	// changelogFooterFormat: |
+	//   {{- range .Versions }}
+	//   {{- if .PreviousVersion }}
+	//   [{{.VersionNoPrefix}}]: https://github.com/miniscruff/changie/compare/{{.PreviousVersion}}...{{.Version}}
+	//   {{- end}}
+	//   {{- end}}
+	ChangelogFooterFormat string `yaml:"changelogFooterFormat,omitempty" templateType:"ChangelogData"`
+	// Changelog archive keeps the merged changelog short by moving older versions
+	// into archive files per major version.
+	// example: yaml
+	// changelogArchive:
+	//   keepCurrentMajor: true
+	ChangelogArchive *ChangelogArchiveConfig `yaml:"changelogArchive,omitempty"`
 	// File extension for generated version files.
 	// This should probably match your changelog path file.
 	// Must not include the period.
@@ -296,15 +547,69 @@ type Config struct {
 	// Customize the file name generated for new versions or release note files.
 	// The file is placed in the [changesDir](#config-changesdir), so the full path is:
 	// `{{.ChangesDir}}/{{.VersionFileFormat}}`
+	// The format is also used to find existing versions, so it must include either
+	// `{{.Version}}` or `{{.VersionNoPrefix}}` exactly once and no other changing values.
+	// Subdirectories are supported.
+	// example: yaml
+	// versionFileFormat: "{{.Version}}/notes.md"
 	VersionFileFormat string `yaml:"versionFileFormat,omitempty" default:"{{.Version}}.{{config.VersionExt}}" templateType:"BatchData"` //nolint:lll
+	// Version frontmatter writes a yaml frontmatter block at the top of new version files,
+	// including the release time, project, previous version, number of changes and the
+	// change fragments, which are searched by `find`.
+	// The frontmatter is removed when merging or diffing versions, and the release time
+	// is used by `versions`, `diff --since` and the combined changelog.
+	// When disabled, version files are merged as is, including any other frontmatter.
+	// example: yaml
+	// versionFrontmatter: true
+	VersionFrontmatter bool `yaml:"versionFrontmatter,omitempty" default:"false"`
 	// Customize the file name generated for new fragments.
 	// The default uses the component and kind only if configured for your project.
 	// The file is placed in the unreleased directory, so the full path is:
 	//
-	// `{{.ChangesDir}}/{{.UnreleasedDir}}/{{.FragmentFileFormat}}.yaml`
+	// `{{.ChangesDir}}/{{.UnreleasedDir}}/{{.FragmentFileFormat}}.{{.FragmentExt}}`
+	//
+	// If a fragment with the same name already exists, such as two changes created in the
+	// same second, a numbered suffix is added to keep each file unique.
 	// example: yaml
 	// fragmentFileFormat: "{{.Kind}}-{{.Custom.Issue}}"
 	FragmentFileFormat string `yaml:"fragmentFileFormat,omitempty" default:"{{.Project}}-{{.Component}}-{{.Kind}}-{{.Time.Format \"20060102-150405\"}}" templateType:"Change"` //nolint:lll
+	// File extension, and format, used for new change fragments.
+	// Fragments of any supported format are read when batching regardless of this value.
+	// Possible values are yaml, yml, json or toml.
+	// Must not include the period.
+	// example: yaml
+	// fragmentExt: json
+	FragmentExt string `yaml:"fragmentExt,omitempty" default:"yaml"`
+	// Fragment subdirs allows slashes in the fragment file format to create subdirectories
+	// inside the unreleased directory, instead of replacing them with dashes.
+	// Fragments are always found in subdirectories of the unreleased directory.
+	// example: yaml
+	// fragmentSubdirs: true
+	// fragmentFileFormat: "{{.Custom.Team}}/{{.Kind}}-{{.Time.Format \"20060102-150405\"}}"
+	FragmentSubdirs bool `yaml:"fragmentSubdirs,omitempty" default:"false"`
+	// Fragment ignore is a list of file path patterns to skip when searching for fragments.
+	// Patterns are matched against the path relative to the unreleased, or included,
+	// directory as well as the file or directory name.
+	// Matching directories are skipped entirely.
+	// Hidden files and directories are always skipped.
+	// example: yaml
+	// fragmentIgnore:
+	//   - drafts
+	//   - "*.tmp.yaml"
+	FragmentIgnore []string `yaml:"fragmentIgnore,omitempty"`
+	// Strict fragments will fail instead of warn when a file in the unreleased directory
+	// is not a supported change fragment.
+	// Unknown files are reported by `batch`, `next auto` and `fragments list`.
+	// Hidden files, such as `.gitkeep`, and the version header and footer files, including
+	// those passed to `batch` with `--header-path` and `--footer-path`, are always allowed.
+	StrictFragments bool `yaml:"strictFragments,omitempty" default:"false"`
+	// Shared project fragments stores a single fragment with a list of projects when a change
+	// affects multiple projects, instead of one fragment per project.
+	// Batching a project removes it from the list and the fragment is removed once every
+	// project has been batched.
+	// example: yaml
+	// sharedProjectFragments: true
+	SharedProjectFragments bool `yaml:"sharedProjectFragments,omitempty" default:"false"`
 	// Template used to generate version headers.
 	VersionFormat string `yaml:"versionFormat,omitempty" templateType:"BatchData"`
 	// Template used to generate component headers.
@@ -342,12 +647,15 @@ type Config struct {
 	// An example could be splitting your changelogs by packages for a monorepo.
 	// If no components are listed then the component prompt will be skipped and no component header included.
 	// By default no components are configured.
+	// Components can be plain strings or objects with a key, label, formats and choices.
 	// example: yaml
 	// components:
 	// - API
 	// - CLI
-	// - Frontend
-	Components []string `yaml:"components,omitempty"`
+	// - key: web
+	//   label: Frontend
+	//   description: Web and mobile clients
+	Components []ComponentConfig `yaml:"components,omitempty"`
 	// Kinds are another optional layer of changelogs suited for specifying what type of change we are
 	// making.
 	// If configured, developers will be prompted to select a kind.
@@ -362,6 +670,32 @@ type Config struct {
 	// - label: Fixed
 	// - label: Security
 	Kinds []KindConfig `yaml:"kinds,omitempty"`
+	// Initial development follows the semver convention for versions with a major of 0,
+	// where anything may change at any time.
+	// While the latest version is 0.x, auto levels of major bump the minor version
+	// and auto levels of minor bump the patch version.
+	// Use `--allow-major` with `batch auto` or `next auto` to release 1.0.0.
+	// example: yaml
+	// initialDevelopment: true
+	InitialDevelopment bool `yaml:"initialDevelopment,omitempty" default:"false"`
+	// Version source is where released versions are read from, either `files` or `tags`.
+	// Files uses the version files in [changesDir](#config-changesdir).
+	// Tags uses git tags, for repos that tag releases outside of changie or only keep
+	// version files of recent releases.
+	// When using projects, only tags starting with the project key and
+	// [projectsVersionSeparator](#config-projectsversionseparator) are used.
+	// Affects the latest version used by `latest`, `next` and `batch`.
+	// example: yaml
+	// versionSource: tags
+	VersionSource string `yaml:"versionSource,omitempty" default:"files"`
+	// Version order is the order versions are written by `merge` and `diff`.
+	// Descending and ascending sort by semantic version.
+	// Date-descending and date-ascending sort by release date, for maintenance releases such as
+	// `v1.9.5` released after `v2.1.0`, using the same release date as `diff --since`.
+	// Can be overridden using the `--order` flag of `merge` and `diff`.
+	// example: yaml
+	// versionOrder: date-descending
+	VersionOrder string `yaml:"versionOrder,omitempty" default:"descending"`
 	// Custom choices allow you to ask for additional information when creating a new change fragment.
 	// These custom choices are included in the [change custom](#change-custom) value.
 	// example: yaml
@@ -417,13 +751,22 @@ type Config struct {
 	// example: yaml
 	// projectsVersionSeparator: "_"
 	ProjectsVersionSeparator string `yaml:"projectsVersionSeparator,omitempty"`
+	// Combined changelog writes an additional changelog including the releases of every project
+	// when merging.
+	// Only used if projects are configured.
+	// example: yaml
+	// combinedChangelog:
+	//   path: CHANGELOG.md
+	//   dateFormat: '## {{.Time.Format "2006-01-02"}}'
+	//   releaseFormat: "### {{.Project}} {{.Version}}"
+	CombinedChangelog *CombinedChangelogConfig `yaml:"combinedChangelog,omitempty"`
 
 	cachedEnvVars map[string]string
 }
 
 func (c *Config) KindFromKeyOrLabel(keyOrLabel string) *KindConfig {
 	for _, kc := range c.Kinds {
-		if kc.KeyOrLabel() == keyOrLabel {
+		if kc.KeyOrLabel() == keyOrLabel || slices.Contains(kc.Aliases, keyOrLabel) {
 			return &kc
 		}
 	}
@@ -431,6 +774,61 @@ func (c *Config) KindFromKeyOrLabel(keyOrLabel string) *KindConfig {
 	return nil
 }
 
+// ProjectsForPaths returns the keys of every project with a path pattern matching
+// any of the files.
+func (c *Config) ProjectsForPaths(files []string) []string {
+	var keys []string
+
+	for _, pc := range c.Projects {
+		if MatchAnyPath(pc.Paths, files) {
+			keys = append(keys, pc.Key)
+		}
+	}
+
+	return keys
+}
+
+// ComponentsForPaths returns the key, or label, of every component with a path pattern
+// matching any of the files.
+func (c *Config) ComponentsForPaths(files []string) []string {
+	var keys []string
+
+	for _, cc := range c.Components {
+		if MatchAnyPath(cc.Paths, files) {
+			keys = append(keys, cc.KeyOrLabel())
+		}
+	}
+
+	return keys
+}
+
+// ComponentFromKeyOrLabel returns the component config for a component key, label or alias.
+// Nil is returned if the component is not configured.
+func (c *Config) ComponentFromKeyOrLabel(keyOrLabel string) *ComponentConfig {
+	if keyOrLabel == "" {
+		return nil
+	}
+
+	for _, cc := range c.Components {
+		if cc.Key == keyOrLabel || cc.Label == keyOrLabel || slices.Contains(cc.Aliases, keyOrLabel) {
+			return &cc
+		}
+	}
+
+	return nil
+}
+
+// ComponentHeader returns the header format for a component, using the component format
+// if set, otherwise the root component format.
+func (c *Config) ComponentHeader(keyOrLabel string) string {
+	cc := c.ComponentFromKeyOrLabel(keyOrLabel)
+	if cc != nil && cc.Format != "" {
+		return cc.Format
+	}
+
+	return c.ComponentFormat
+}
+
 func (c *Config) KindHeader(keyOrLabel string) string {
 	for _, kc := range c.Kinds {
 		if kc.Format != "" && (kc.Key == keyOrLabel || kc.Label == keyOrLabel) {
@@ -451,6 +849,46 @@ func (c *Config) ChangeFormatForKind(keyOrLabel string) string {
 	return c.ChangeFormat
 }
 
+// FragmentPath returns the path a change fragment is saved to using the fragment file format.
+// Slashes are replaced to keep all fragments inside the unreleased directory,
+// unless fragment subdirs are enabled.
+func (c *Config) FragmentPath(cache *TemplateCache, change *Change) (string, error) {
+	ext := c.FragmentExtOrDefault()
+	if !slices.Contains(FragmentExts, ext) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownFragmentExt, ext)
+	}
+
+	filename, err := cache.ExecuteString(c.FragmentFileFormat, change)
+	if err != nil {
+		return "", err
+	}
+
+	filename += "." + ext
+
+	if c.FragmentSubdirs {
+		// Subdirectories are allowed, but the fragment must stay in the unreleased directory.
+		filename = filepath.Clean(filepath.FromSlash(filename))
+		if !filepath.IsLocal(filename) {
+			return "", fmt.Errorf("%w: %s", ErrFragmentOutsideUnreleased, filename)
+		}
+	} else {
+		// Sanatize the filename to remove invalid characters such as slashes
+		replacer := strings.NewReplacer("/", "-", "\\", "-")
+		filename = replacer.Replace(filename)
+	}
+
+	return filepath.Join(c.ChangesDir, c.UnreleasedDir, filename), nil
+}
+
+// FragmentExtOrDefault returns the fragment extension, defaulting to yaml if none is configured.
+func (c *Config) FragmentExtOrDefault() string {
+	if c.FragmentExt == "" {
+		return "yaml"
+	}
+
+	return c.FragmentExt
+}
+
 func (c *Config) EnvVars() map[string]string {
 	if c.cachedEnvVars == nil {
 		c.cachedEnvVars = LoadEnvVars(c, os.Environ())
@@ -475,7 +913,7 @@ func (c *Config) Project(labelOrKey string) (*ProjectConfig, error) {
 	}
 
 	for _, pc := range c.Projects {
-		if labelOrKey == pc.Label || labelOrKey == pc.Key {
+		if labelOrKey == pc.Label || labelOrKey == pc.Key || slices.Contains(pc.Aliases, labelOrKey) {
 			return &pc, nil
 		}
 	}
@@ -483,6 +921,83 @@ func (c *Config) Project(labelOrKey string) (*ProjectConfig, error) {
 	return nil, errProjectNotFound
 }
 
+// ForChange returns the effective config for the project of a change, as projects can
+// override kinds and components.
+// The config itself is returned if the change has no configured project.
+func (c *Config) ForChange(change Change) *Config {
+	project := change.firstProject()
+	if len(c.Projects) == 0 || project == "" {
+		return c
+	}
+
+	projectCfg, err := c.ForProject(project)
+	if err != nil {
+		return c
+	}
+
+	return projectCfg
+}
+
+// ForProject returns the effective config for a project, which is a copy of the config
+// with any project overrides applied.
+// The config itself is returned if no projects are configured.
+func (c *Config) ForProject(labelOrKey string) (*Config, error) {
+	if len(c.Projects) == 0 {
+		return c, nil
+	}
+
+	pc, err := c.Project(labelOrKey)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := *c
+
+	if pc.Kinds != nil {
+		effective.Kinds = pc.Kinds
+	}
+
+	if pc.Components != nil {
+		effective.Components = pc.Components
+	}
+
+	if pc.CustomChoices != nil {
+		effective.CustomChoices = pc.CustomChoices
+	}
+
+	for _, value := range []struct {
+		root    *string
+		project string
+	}{
+		{&effective.VersionFormat, pc.VersionFormat},
+		{&effective.ComponentFormat, pc.ComponentFormat},
+		{&effective.KindFormat, pc.KindFormat},
+		{&effective.ChangeFormat, pc.ChangeFormat},
+		{&effective.HeaderFormat, pc.HeaderFormat},
+		{&effective.FooterFormat, pc.FooterFormat},
+		{&effective.HeaderPath, pc.HeaderPath},
+	} {
+		if value.project != "" {
+			*value.root = value.project
+		}
+	}
+
+	if pc.Newlines != nil {
+		effective.Newlines = *pc.Newlines
+	}
+
+	if pc.VersionExt != "" {
+		// keep the default version file format in sync with the extension
+		if c.VersionFileFormat == "{{.Version}}."+c.VersionExt {
+			effective.VersionFileFormat = "{{.Version}}." + pc.VersionExt
+		}
+
+		effective.VersionExt = pc.VersionExt
+	}
+
+	return &effective, nil
+}
+
 func (c *Config) ProjectLabels() []string {
 	projectLabels := make([]string, len(c.Projects))
 
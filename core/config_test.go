@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/miniscruff/changie/then"
 )
 
@@ -51,7 +54,7 @@ func TestSaveConfigWithCustomChoicesAndOptionals(t *testing.T) {
 		ComponentFormat:   "comp",
 		KindFormat:        "kind",
 		ChangeFormat:      "chng",
-		Components:        []string{"A", "D", "G"},
+		Components:        []ComponentConfig{{Label: "A"}, {Label: "D"}, {Label: "G"}},
 		HeaderFormat:      "head",
 		FooterFormat:      "foot",
 		Kinds: []KindConfig{
@@ -265,6 +268,81 @@ func TestErrorBadYamlFile(t *testing.T) {
 	then.NotNil(t, err)
 }
 
+func TestFragmentPathReplacesSlashes(t *testing.T) {
+	config := Config{
+		ChangesDir:         ".changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Component}}-{{.Kind}}",
+	}
+
+	path, err := config.FragmentPath(NewTemplateCache(), &Change{
+		Component: "api/v2",
+		Kind:      "added",
+	})
+	then.Nil(t, err)
+	then.Equals(t, filepath.Join(".changes", "unreleased", "api-v2-added.yaml"), path)
+}
+
+func TestFragmentPathKeepsSubdirs(t *testing.T) {
+	config := Config{
+		ChangesDir:         ".changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Component}}/{{.Kind}}",
+		FragmentSubdirs:    true,
+	}
+
+	path, err := config.FragmentPath(NewTemplateCache(), &Change{
+		Component: "api",
+		Kind:      "added",
+	})
+	then.Nil(t, err)
+	then.Equals(t, filepath.Join(".changes", "unreleased", "api", "added.yaml"), path)
+}
+
+func TestErrorFragmentPathSubdirsOutsideUnreleased(t *testing.T) {
+	config := Config{
+		ChangesDir:         ".changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "../{{.Kind}}",
+		FragmentSubdirs:    true,
+	}
+
+	_, err := config.FragmentPath(NewTemplateCache(), &Change{Kind: "added"})
+	then.Err(t, ErrFragmentOutsideUnreleased, err)
+}
+
+func TestFragmentPathUsesFragmentExt(t *testing.T) {
+	config := Config{
+		ChangesDir:         ".changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Kind}}",
+		FragmentExt:        "json",
+	}
+
+	path, err := config.FragmentPath(NewTemplateCache(), &Change{Kind: "added"})
+	then.Nil(t, err)
+	then.Equals(t, filepath.Join(".changes", "unreleased", "added.json"), path)
+}
+
+func TestErrorFragmentPathBadExt(t *testing.T) {
+	config := Config{
+		FragmentFileFormat: "{{.Kind}}",
+		FragmentExt:        "xml",
+	}
+
+	_, err := config.FragmentPath(NewTemplateCache(), &Change{})
+	then.Err(t, ErrUnknownFragmentExt, err)
+}
+
+func TestErrorFragmentPathBadTemplate(t *testing.T) {
+	config := Config{
+		FragmentFileFormat: "{{...bad}}",
+	}
+
+	_, err := config.FragmentPath(NewTemplateCache(), &Change{})
+	then.NotNil(t, err)
+}
+
 func TestGetHeaderFromKindLabel(t *testing.T) {
 	config := Config{
 		Kinds: []KindConfig{
@@ -396,6 +474,58 @@ func TestConfigProjectFindsProjectByKey(t *testing.T) {
 	then.Equals(t, "CLI", proj.Label)
 }
 
+func TestConfigProjectFindsProjectByAlias(t *testing.T) {
+	cfg := &Config{
+		Projects: []ProjectConfig{
+			{
+				Label:   "CLI",
+				Key:     "cli",
+				Aliases: []string{"command"},
+			},
+		},
+	}
+
+	proj, err := cfg.Project("command")
+	then.Nil(t, err)
+	then.Equals(t, "cli", proj.Key)
+}
+
+func TestConfigComponentFindsComponentOrAlias(t *testing.T) {
+	cfg := &Config{
+		Components: []ComponentConfig{
+			{Label: "api", Aliases: []string{"backend"}},
+			{Key: "cli", Label: "CLI", Aliases: []string{"command"}},
+		},
+	}
+
+	for input, expected := range map[string]string{
+		"api":     "api",
+		"cli":     "cli",
+		"CLI":     "cli",
+		"command": "cli",
+		"backend": "api",
+	} {
+		cc := cfg.ComponentFromKeyOrLabel(input)
+		then.NotNil(t, cc)
+		then.Equals(t, expected, cc.KeyOrLabel())
+	}
+
+	then.True(t, cfg.ComponentFromKeyOrLabel("old") == nil)
+}
+
+func TestKindFromKeyOrLabelFindsAlias(t *testing.T) {
+	cfg := &Config{
+		Kinds: []KindConfig{
+			{Label: "Added", Aliases: []string{"Feature"}},
+		},
+	}
+
+	kc := cfg.KindFromKeyOrLabel("Feature")
+	then.NotNil(t, kc)
+	then.Equals(t, "Added", kc.Label)
+	then.True(t, cfg.KindFromKeyOrLabel("Missing") == nil)
+}
+
 func TestConfigProjectNoProjectsReturnsEmptyConfig(t *testing.T) {
 	cfg := &Config{}
 
@@ -406,6 +536,82 @@ func TestConfigProjectNoProjectsReturnsEmptyConfig(t *testing.T) {
 	then.Equals(t, "", proj.ChangelogPath)
 }
 
+func TestConfigForProjectAppliesOverrides(t *testing.T) {
+	newlines := NewlinesConfig{AfterKind: 2}
+	cfg := &Config{
+		Kinds:             []KindConfig{{Label: "Added"}},
+		Components:        []ComponentConfig{{Label: "API"}},
+		CustomChoices:     []Custom{{Key: "Issue"}},
+		ChangeFormat:      "* {{.Body}}",
+		KindFormat:        "## {{.Kind}}",
+		VersionExt:        "md",
+		VersionFileFormat: "{{.Version}}.md",
+		Projects: []ProjectConfig{
+			{
+				Label:        "CLI",
+				Key:          "cli",
+				Kinds:        []KindConfig{{Label: "Command"}},
+				ChangeFormat: "- {{.Body}}",
+				Newlines:     &newlines,
+				HeaderPath:   "cli-header.md",
+				VersionExt:   "txt",
+			},
+			{
+				Label: "SDK",
+				Key:   "sdk",
+			},
+		},
+	}
+
+	cli, err := cfg.ForProject("CLI")
+	then.Nil(t, err)
+	then.Equals(t, "Command", cli.Kinds[0].Label)
+	then.Equals(t, "API", cli.Components[0].Label)
+	then.Equals(t, "Issue", cli.CustomChoices[0].Key)
+	then.Equals(t, "- {{.Body}}", cli.ChangeFormat)
+	then.Equals(t, "## {{.Kind}}", cli.KindFormat)
+	then.Equals(t, 2, cli.Newlines.AfterKind)
+	then.Equals(t, "cli-header.md", cli.HeaderPath)
+	then.Equals(t, "txt", cli.VersionExt)
+	then.Equals(t, "{{.Version}}.txt", cli.VersionFileFormat)
+
+	// the root config is not modified
+	then.Equals(t, "Added", cfg.Kinds[0].Label)
+	then.Equals(t, "md", cfg.VersionExt)
+
+	sdk, err := cfg.ForProject("sdk")
+	then.Nil(t, err)
+	then.Equals(t, "Added", sdk.Kinds[0].Label)
+	then.Equals(t, "* {{.Body}}", sdk.ChangeFormat)
+}
+
+func TestConfigForProjectKeepsCustomVersionFileFormat(t *testing.T) {
+	cfg := &Config{
+		VersionExt:        "md",
+		VersionFileFormat: "release-{{.Version}}.md",
+		Projects:          []ProjectConfig{{Key: "cli", VersionExt: "txt"}},
+	}
+
+	cli, err := cfg.ForProject("cli")
+	then.Nil(t, err)
+	then.Equals(t, "release-{{.Version}}.md", cli.VersionFileFormat)
+}
+
+func TestConfigForProjectWithoutProjects(t *testing.T) {
+	cfg := &Config{}
+
+	projectCfg, err := cfg.ForProject("")
+	then.Nil(t, err)
+	then.True(t, cfg == projectCfg)
+}
+
+func TestErrorConfigForProjectNotFound(t *testing.T) {
+	cfg := &Config{Projects: []ProjectConfig{{Key: "cli"}}}
+
+	_, err := cfg.ForProject("missing")
+	then.Err(t, errProjectNotFound, err)
+}
+
 func TestConfigProjectCanGetLabels(t *testing.T) {
 	cfg := &Config{
 		Projects: []ProjectConfig{
@@ -451,6 +657,61 @@ func TestConfigProjectErrorNotFound(t *testing.T) {
 	then.Err(t, errProjectNotFound, err)
 }
 
+func TestComponentsLoadFromStringsOrObjects(t *testing.T) {
+	var cfg Config
+
+	err := yaml.Unmarshal([]byte(`components:
+  - API
+  - key: web
+    label: Frontend
+    description: Web clients
+    format: "## Web"
+`), &cfg)
+	then.Nil(t, err)
+	then.SliceLen(t, 2, cfg.Components)
+	then.Equals(t, "API", cfg.Components[0].KeyOrLabel())
+	then.Equals(t, "web", cfg.Components[1].KeyOrLabel())
+	then.Equals(t, "Frontend", cfg.Components[1].Label)
+	then.Equals(t, "Web clients", cfg.Components[1].Description)
+	then.Equals(t, "## Web", cfg.ComponentHeader("web"))
+}
+
+func TestComponentsSaveLabelOnlyAsStrings(t *testing.T) {
+	cfg := Config{
+		Components: []ComponentConfig{
+			{Label: "API"},
+			{Key: "web", Label: "Frontend"},
+		},
+	}
+
+	bs, err := yaml.Marshal(&cfg)
+	then.Nil(t, err)
+	then.True(t, strings.Contains(string(bs), "components:\n    - API\n    - key: web\n      label: Frontend\n"))
+}
+
+func TestErrorComponentsBadYaml(t *testing.T) {
+	var cfg Config
+
+	err := yaml.Unmarshal([]byte("components:\n  - key: [not, a, string]\n"), &cfg)
+	then.NotNil(t, err)
+}
+
+func TestComponentHeaderDefaultsToComponentFormat(t *testing.T) {
+	cfg := Config{
+		ComponentFormat: "## {{.Component}}",
+		Components:      []ComponentConfig{{Label: "API"}},
+	}
+
+	then.Equals(t, "## {{.Component}}", cfg.ComponentHeader("API"))
+	then.Equals(t, "## {{.Component}}", cfg.ComponentHeader("missing"))
+}
+
+func TestErrorComponentAutoLevelForChangeInvalidRender(t *testing.T) {
+	cc := ComponentConfig{Label: "API", AutoLevel: "Major"}
+	_, err := cc.AutoLevelForChange(NewTemplateCache(), Change{})
+	then.Err(t, ErrInvalidAutoLevel, err)
+}
+
 func TestAutoLevelForChangeLiteral(t *testing.T) {
 	kc := KindConfig{AutoLevel: MinorLevel}
 	level, err := kc.AutoLevelForChange(NewTemplateCache(), Change{})
@@ -501,3 +762,23 @@ func TestErrorAutoLevelForChangeEmptyRender(t *testing.T) {
 	_, err := kc.AutoLevelForChange(NewTemplateCache(), Change{})
 	then.NotNil(t, err)
 }
+
+func TestProjectsAndComponentsForPaths(t *testing.T) {
+	cfg := &Config{
+		Projects: []ProjectConfig{
+			{Key: "api", Paths: []string{"api/**"}},
+			{Key: "web", Paths: []string{"web/**", "shared/**"}},
+			{Key: "docs"},
+		},
+		Components: []ComponentConfig{
+			{Key: "proto", Label: "Protocol", Paths: []string{"api/*.proto"}},
+			{Label: "Shared", Paths: []string{"shared/**"}},
+		},
+	}
+
+	files := []string{"api/service.proto", "shared/util.go"}
+
+	then.SliceEquals(t, []string{"api", "web"}, cfg.ProjectsForPaths(files))
+	then.SliceEquals(t, []string{"proto", "Shared"}, cfg.ComponentsForPaths(files))
+	then.SliceLen(t, 0, cfg.ProjectsForPaths([]string{"README.md"}))
+}
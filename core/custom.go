@@ -118,30 +118,30 @@ func (c Custom) DisplayLabel() string {
 	return c.Label
 }
 
-func (c Custom) askString(stdinReader io.Reader) (string, error) {
+func (c Custom) askString(stdinReader io.Reader, value string) (string, error) {
 	return prompt.New().Ask(c.DisplayLabel()).
 		Input(
-			"",
+			value,
 			input.WithHelp(true),
 			input.WithValidateFunc(c.validateString),
 			input.WithTeaProgramOpts(tea.WithInput(stdinReader)),
 		)
 }
 
-func (c Custom) askBlock(stdinReader io.Reader) (string, error) {
+func (c Custom) askBlock(stdinReader io.Reader, value string) (string, error) {
 	return prompt.New().Ask(c.DisplayLabel()).
 		Write(
-			"",
+			value,
 			write.WithHelp(true),
 			write.WithValidateFunc(c.validateString),
 			write.WithTeaProgramOpts(tea.WithInput(stdinReader)),
 		)
 }
 
-func (c Custom) askInt(stdinReader io.Reader) (string, error) {
+func (c Custom) askInt(stdinReader io.Reader, value string) (string, error) {
 	return prompt.New().Ask(c.DisplayLabel()).
 		Input(
-			"",
+			value,
 			input.WithHelp(true),
 			input.WithInputMode(input.InputInteger),
 			input.WithValidateFunc(c.validateInt),
@@ -183,14 +183,26 @@ func ThemeScroll(choices []choose.Choice, cursor int) string {
 	// We will determine if the item is selected or not by calling isSelected
 	// with the index of the item, but since we're only displaying a slice of
 	// the items we need to offset the index.
+	// Notes are aligned after the longest choice, the same as the default theme.
+	maxLen := 0
+	for _, choice := range choices[start:end] {
+		maxLen = max(maxLen, len([]rune(choice.Text)))
+	}
+
 	for i, choice := range choices[start:end] {
 		// Check if the item is the one at the cursor's location
 		isCursor := index == i
 
+		text := choice.Text
+		if choice.Note != "" {
+			text += strings.Repeat(" ", maxLen-len([]rune(choice.Text))+2) +
+				constants.DefaultNoteStyle.Render(choice.Note)
+		}
+
 		if isCursor {
-			s.WriteString(constants.DefaultSelectedItemStyle.Render(fmt.Sprintf("• %s", choice.Text)))
+			s.WriteString(constants.DefaultSelectedItemStyle.Render(fmt.Sprintf("• %s", text)))
 		} else {
-			s.WriteString(constants.DefaultItemStyle.Render(fmt.Sprintf("  %s", choice.Text)))
+			s.WriteString(constants.DefaultItemStyle.Render(fmt.Sprintf("  %s", text)))
 		}
 
 		s.WriteString("\n")
@@ -199,22 +211,34 @@ func ThemeScroll(choices []choose.Choice, cursor int) string {
 	return s.String()
 }
 
-func (c Custom) askEnum(stdinReader io.Reader) (string, error) {
+func (c Custom) askEnum(stdinReader io.Reader, value string) (string, error) {
 	return prompt.New().Ask(c.DisplayLabel()).
 		Choose(
 			c.EnumOptions,
 			choose.WithHelp(true),
 			choose.WithTeaProgramOpts(tea.WithInput(stdinReader)),
 			choose.WithTheme(ThemeScroll),
+			choose.WithDefaultIndex(max(slices.Index(c.EnumOptions, value), 0)),
 		)
 }
 
-func (c Custom) askEnums(stdinReader io.Reader) (string, error) {
+func (c Custom) askEnums(stdinReader io.Reader, value string) (string, error) {
+	selected := make([]int, 0)
+
+	if value != "" {
+		for _, v := range strings.Split(value, enumsSeparator) {
+			if i := slices.Index(c.EnumOptions, v); i >= 0 {
+				selected = append(selected, i)
+			}
+		}
+	}
+
 	values, err := prompt.New().Ask(c.DisplayLabel()).
 		MultiChoose(
 			c.EnumOptions,
 			multichoose.WithHelp(true),
 			multichoose.WithTeaProgramOpts(tea.WithInput(stdinReader)),
+			multichoose.WithDefaultIndexes(0, selected),
 		)
 	if err != nil {
 		return "", err
@@ -223,19 +247,25 @@ func (c Custom) askEnums(stdinReader io.Reader) (string, error) {
 	return strings.Join(values, enumsSeparator), nil
 }
 
-// CreatePrompt will create a promptui select or prompt from a custom choice
+// AskPrompt will create a prompt from a custom choice and return the answer
 func (c Custom) AskPrompt(stdinReader io.Reader) (string, error) {
+	return c.AskPromptWithDefault(stdinReader, "")
+}
+
+// AskPromptWithDefault will create a prompt from a custom choice pre-filled with a value,
+// such as when editing an existing change.
+func (c Custom) AskPromptWithDefault(stdinReader io.Reader, value string) (string, error) {
 	switch c.Type {
 	case CustomString:
-		return c.askString(stdinReader)
+		return c.askString(stdinReader, value)
 	case CustomBlock:
-		return c.askBlock(stdinReader)
+		return c.askBlock(stdinReader, value)
 	case CustomInt:
-		return c.askInt(stdinReader)
+		return c.askInt(stdinReader, value)
 	case CustomEnum:
-		return c.askEnum(stdinReader)
+		return c.askEnum(stdinReader, value)
 	case CustomEnums:
-		return c.askEnums(stdinReader)
+		return c.askEnums(stdinReader, value)
 	}
 
 	return "", errInvalidPromptType
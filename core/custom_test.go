@@ -81,6 +81,20 @@ func TestCreateCustomIntPrompt(t *testing.T) {
 	then.Equals(t, value, "15")
 }
 
+func TestCreateCustomStringPromptWithDefault(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		[]byte{13}, // 13=enter
+	)
+
+	custom := Custom{Type: CustomString, Key: "name"}
+	value, err := custom.AskPromptWithDefault(reader, "existing")
+
+	then.Nil(t, err)
+	then.Equals(t, "existing", value)
+}
+
 func TestCanRunBlockPrompt(t *testing.T) {
 	reader, writer := then.WithReadWritePipe(t)
 	then.DelayWrite(
@@ -113,6 +127,36 @@ func TestCanRunEnumPrompt(t *testing.T) {
 	then.Equals(t, "b", value)
 }
 
+func TestCanRunEnumPromptWithDefault(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		[]byte{106, 13}, // 106 = down, 13 = enter
+	)
+
+	opts := []string{"a", "b", "c"}
+	custom := Custom{Type: CustomEnum, EnumOptions: opts}
+
+	value, err := custom.AskPromptWithDefault(reader, "b")
+	then.Nil(t, err)
+	then.Equals(t, "c", value)
+}
+
+func TestCanRunEnumsPromptWithDefault(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		[]byte{106, 106, 32, 13}, // 106=down, 32=space, 13=enter
+	)
+
+	opts := []string{"a", "b", "c"}
+	custom := Custom{Type: CustomEnums, EnumOptions: opts}
+
+	value, err := custom.AskPromptWithDefault(reader, "a")
+	then.Nil(t, err)
+	then.Equals(t, "a, c", value)
+}
+
 func TestCanRunEnumsPrompt(t *testing.T) {
 	reader, writer := then.WithReadWritePipe(t)
 	then.DelayWrite(
@@ -416,6 +460,19 @@ func TestThemeScrollWithFewerThan10Choices(t *testing.T) {
 	then.False(t, strings.Contains(result, "Option 4"))
 }
 
+func TestThemeScrollWithNotes(t *testing.T) {
+	choices := []choose.Choice{
+		{Text: "API", Note: "Public endpoints"},
+		{Text: "Frontend"},
+	}
+
+	result := ThemeScroll(choices, 0)
+
+	then.True(t, strings.Contains(result, "• API"))
+	then.True(t, strings.Contains(result, "Public endpoints"))
+	then.True(t, strings.Contains(result, "  Frontend"))
+}
+
 func TestThemeScrollWithExactly10Choices(t *testing.T) {
 	choices := make([]choose.Choice, 10)
 	for i := range 10 {
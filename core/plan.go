@@ -0,0 +1,241 @@
+package core
+
+import "errors"
+
+// Reasons a project is skipped when planning all projects.
+const (
+	SkipNoChanges       = "no changes"
+	SkipNoAutoLevel     = "no changes with an auto level"
+	SkipNoReleaseNeeded = "no release needed"
+)
+
+// ProjectPlan is the planned release of a single project when releasing all projects.
+type ProjectPlan struct {
+	// Project key
+	Project string `json:"project"`
+	// Project label
+	Label string `json:"label"`
+	// Previous version of the project, or v0.0.0 if not yet released
+	PreviousVersion string `json:"previousVersion"`
+	// Next version of the project, empty if the project is skipped
+	NextVersion string `json:"nextVersion,omitempty"`
+	// Number of unreleased changes for the project
+	Changes int `json:"changes"`
+	// Skipped is true if the project has no unreleased changes, or none that require a release
+	Skipped bool `json:"skipped"`
+	// Reason the project was skipped
+	Reason string `json:"reason,omitempty"`
+	// ReleaseNeeded is false if the project has no changes, or only changes with an auto level of none
+	ReleaseNeeded bool `json:"releaseNeeded"`
+}
+
+// PlanProjects determines the previous and next version of every configured project.
+// Projects without any unreleased changes, or only changes with an auto level of none,
+// are skipped and do not have a next version.
+func PlanProjects(
+	config *Config,
+	cache *TemplateCache,
+	partOrVersion string,
+	allowMajor bool,
+	prerelease, meta []string,
+	searchPaths []string,
+) ([]ProjectPlan, error) {
+	plans := make([]ProjectPlan, 0, len(config.Projects))
+
+	for _, pc := range config.Projects {
+		projectConfig, err := config.ForProject(pc.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		previous, err := GetLatestVersion(projectConfig, false, pc.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		changes, err := GetChanges(projectConfig, searchPaths, pc.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		plan := ProjectPlan{
+			Project:         pc.Key,
+			Label:           pc.Label,
+			PreviousVersion: previous.Original(),
+			Changes:         len(changes),
+		}
+
+		if len(changes) == 0 {
+			plan.Skipped = true
+			plan.Reason = SkipNoChanges
+			plans = append(plans, plan)
+
+			continue
+		}
+
+		next, err := GetNextVersion(
+			projectConfig, cache, partOrVersion, allowMajor, prerelease, meta, changes, pc.Key)
+
+		switch {
+		case errors.Is(err, ErrNoReleaseNeeded):
+			plan.Skipped = true
+			plan.Reason = SkipNoReleaseNeeded
+		case errors.Is(err, ErrNoChangesFoundForAuto):
+			plan.Skipped = true
+			plan.Reason = SkipNoAutoLevel
+		case err != nil:
+			return nil, err
+		default:
+			plan.NextVersion = next.Original()
+			plan.ReleaseNeeded = true
+		}
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// Sources of the auto level of a change.
+const (
+	LevelFromComponent = "component"
+	LevelFromKind      = "kind"
+)
+
+// ChangeLevel is the auto level of a single change and where the level was configured.
+type ChangeLevel struct {
+	// File the change was loaded from
+	File string `json:"file"`
+	// Kind of the change
+	Kind string `json:"kind,omitempty"`
+	// Component of the change
+	Component string `json:"component,omitempty"`
+	// Auto level of the change, empty if neither the kind or component is configured
+	Level string `json:"level"`
+	// Source of the level, either component or kind
+	Source string `json:"source,omitempty"`
+	// Decisive is true if the change has the highest level of all changes
+	Decisive bool `json:"decisive"`
+}
+
+// AutoLevelExplanation explains how the next version was determined from the auto level
+// of each unreleased change.
+type AutoLevelExplanation struct {
+	// Highest auto level of all changes
+	Level string `json:"level"`
+	// Level used to bump the version, lower than level during initial development
+	Bump string `json:"bump"`
+	// Previous version, or v0.0.0 if not yet released
+	PreviousVersion string `json:"previousVersion"`
+	// Next version after bumping, empty if no release is needed
+	NextVersion string `json:"nextVersion"`
+	// ReleaseNeeded is false if every change has an auto level of none
+	ReleaseNeeded bool `json:"releaseNeeded"`
+	// Auto level of every change
+	Changes []ChangeLevel `json:"changes"`
+}
+
+var levelRanks = map[string]int{
+	PatchLevel: 1,
+	MinorLevel: 2,
+	MajorLevel: 3,
+}
+
+// ExplainNextVersion determines the next version using the auto level of changes,
+// the same as GetNextVersion, while recording the level of each change.
+// If every change has an auto level of none, the explanation is returned without a next version.
+func ExplainNextVersion(
+	config *Config,
+	cache *TemplateCache,
+	allowMajor bool,
+	prerelease, meta []string,
+	allChanges []Change,
+	projectKey string,
+) (*AutoLevelExplanation, error) {
+	levels, highest, err := changeLevels(config, cache, allChanges)
+	if err != nil && !errors.Is(err, ErrNoReleaseNeeded) {
+		return nil, err
+	}
+
+	previous, latestErr := GetLatestVersion(config, false, projectKey)
+	if latestErr != nil {
+		return nil, latestErr
+	}
+
+	if err != nil {
+		return &AutoLevelExplanation{
+			Level:           highest,
+			Bump:            highest,
+			PreviousVersion: previous.Original(),
+			Changes:         levels,
+		}, nil
+	}
+
+	bump := highest
+	if config.InitialDevelopment {
+		bump = initialDevelopmentLevel(highest, previous, allowMajor)
+	}
+
+	next, err := GetNextVersion(config, cache, bump, allowMajor, prerelease, meta, allChanges, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AutoLevelExplanation{
+		Level:           highest,
+		Bump:            bump,
+		PreviousVersion: previous.Original(),
+		NextVersion:     next.Original(),
+		ReleaseNeeded:   true,
+		Changes:         levels,
+	}, nil
+}
+
+// changeLevels returns the auto level of every change along with the highest level.
+// Changes with a level equal to the highest are marked as decisive.
+// If every level is none, the levels are returned along with ErrNoReleaseNeeded.
+func changeLevels(config *Config, cache *TemplateCache, allChanges []Change) ([]ChangeLevel, string, error) {
+	if len(allChanges) == 0 {
+		return nil, EmptyLevel, ErrNoChangesFoundForAuto
+	}
+
+	levels := make([]ChangeLevel, 0, len(allChanges))
+	highest := EmptyLevel
+
+	for _, change := range allChanges {
+		level, source, err := changeAutoLevel(config, cache, change)
+		if err != nil {
+			return nil, EmptyLevel, err
+		}
+
+		if source != "" && level == EmptyLevel {
+			return nil, EmptyLevel, ErrMissingAutoLevel
+		}
+
+		if levelRanks[level] > levelRanks[highest] || (level == NoneLevel && highest == EmptyLevel) {
+			highest = level
+		}
+
+		levels = append(levels, ChangeLevel{
+			File:      change.Filename,
+			Kind:      change.Kind,
+			Component: change.Component,
+			Level:     level,
+			Source:    source,
+		})
+	}
+
+	if highest == EmptyLevel {
+		return nil, EmptyLevel, ErrNoChangesFoundForAuto
+	}
+
+	for i := range levels {
+		levels[i].Decisive = levels[i].Level == highest
+	}
+
+	if highest == NoneLevel {
+		return levels, highest, ErrNoReleaseNeeded
+	}
+
+	return levels, highest, nil
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miniscruff/changie/then"
+)
+
+func TestPlanProjectsSkipsProjectsWithoutChanges(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Projects = []ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+	}
+
+	then.CreateFile(t, cfg.ChangesDir, "a", "v1.2.0.md")
+	then.WriteFileTo(t, Change{Project: "a", Kind: "added", Body: "one"},
+		cfg.ChangesDir, cfg.UnreleasedDir, "a.yaml")
+
+	plans, err := PlanProjects(cfg, NewTemplateCache(), "patch", false, nil, nil, nil)
+	then.Nil(t, err)
+	then.SliceEquals(t, []ProjectPlan{
+		{Project: "a", Label: "A", PreviousVersion: "v1.2.0", NextVersion: "v1.2.1", Changes: 1, ReleaseNeeded: true},
+		{Project: "b", Label: "B", PreviousVersion: "v0.0.0", Skipped: true, Reason: SkipNoChanges},
+	}, plans)
+}
+
+func TestPlanProjectsSkipsProjectsWithOnlyNoneChanges(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Kinds = []KindConfig{
+		{Label: "added", AutoLevel: MinorLevel},
+		{Label: "internal", AutoLevel: NoneLevel},
+	}
+	cfg.Projects = []ProjectConfig{
+		{Label: "A", Key: "a"},
+		{Label: "B", Key: "b"},
+	}
+
+	then.WriteFileTo(t, Change{Project: "a", Kind: "internal", Body: "one"},
+		cfg.ChangesDir, cfg.UnreleasedDir, "a.yaml")
+	then.WriteFileTo(t, Change{Project: "b", Kind: "added", Body: "two"},
+		cfg.ChangesDir, cfg.UnreleasedDir, "b.yaml")
+
+	plans, err := PlanProjects(cfg, NewTemplateCache(), AutoLevel, false, nil, nil, nil)
+	then.Nil(t, err)
+	then.SliceEquals(t, []ProjectPlan{
+		{Project: "a", Label: "A", PreviousVersion: "v0.0.0", Changes: 1, Skipped: true, Reason: SkipNoReleaseNeeded},
+		{Project: "b", Label: "B", PreviousVersion: "v0.0.0", NextVersion: "v0.1.0", Changes: 1, ReleaseNeeded: true},
+	}, plans)
+}
+
+func TestPlanProjectsErrorsOnBadChanges(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Projects = []ProjectConfig{{Label: "A", Key: "a"}}
+
+	err := os.MkdirAll(filepath.Join(cfg.ChangesDir, cfg.UnreleasedDir), CreateDirMode)
+	then.Nil(t, err)
+	then.WriteFile(t, []byte("not: [valid"), cfg.ChangesDir, cfg.UnreleasedDir, "a.yaml")
+
+	_, err = PlanProjects(cfg, NewTemplateCache(), "patch", false, nil, nil, nil)
+	then.NotNil(t, err)
+}
+
+func TestExplainNextVersion(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.InitialDevelopment = true
+	cfg.Kinds = []KindConfig{
+		{Label: "added", AutoLevel: MinorLevel},
+		{Label: "fixed", AutoLevel: PatchLevel},
+		{Label: "internal", AutoLevel: NoneLevel},
+	}
+	cfg.Components = []ComponentConfig{
+		{Label: "docs", AutoLevel: PatchLevel},
+	}
+
+	then.CreateFile(t, cfg.ChangesDir, "v0.3.0.md")
+
+	changes := []Change{
+		{Filename: "a.yaml", Kind: "added"},
+		{Filename: "b.yaml", Kind: "added", Component: "docs"},
+		{Filename: "c.yaml", Kind: "internal"},
+		{Filename: "d.yaml", Kind: "added"},
+	}
+
+	explanation, err := ExplainNextVersion(cfg, NewTemplateCache(), false, nil, nil, changes, "")
+	then.Nil(t, err)
+	then.Equals(t, MinorLevel, explanation.Level)
+	then.Equals(t, PatchLevel, explanation.Bump)
+	then.Equals(t, "v0.3.0", explanation.PreviousVersion)
+	then.Equals(t, "v0.3.1", explanation.NextVersion)
+	then.SliceEquals(t, []ChangeLevel{
+		{File: "a.yaml", Kind: "added", Level: MinorLevel, Source: LevelFromKind, Decisive: true},
+		{File: "b.yaml", Kind: "added", Component: "docs", Level: PatchLevel, Source: LevelFromComponent},
+		{File: "c.yaml", Kind: "internal", Level: NoneLevel, Source: LevelFromKind},
+		{File: "d.yaml", Kind: "added", Level: MinorLevel, Source: LevelFromKind, Decisive: true},
+	}, explanation.Changes)
+}
+
+func TestErrorExplainNextVersionMissingAutoLevel(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Kinds = []KindConfig{
+		{Label: "added", AutoLevel: MajorLevel},
+		{Label: "missing"},
+	}
+
+	_, err := ExplainNextVersion(cfg, NewTemplateCache(), false, nil, nil, []Change{
+		{Kind: "added"},
+		{Kind: "missing"},
+	}, "")
+	then.Err(t, ErrMissingAutoLevel, err)
+}
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"path/filepath"
+	"reflect"
 	"runtime"
 	"slices"
+	"strings"
 
 	"github.com/cqroot/prompt"
+	"github.com/cqroot/prompt/choose"
 	"github.com/cqroot/prompt/multichoose"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,6 +27,11 @@ var (
 	errCustomProvidedNotConfigured        = errors.New("custom value provided but not configured")
 	errProjectNotFound                    = errors.New("project not found")
 	errProjectRequired                    = errors.New("project missing but required")
+	errChangeCancelled                    = errors.New("change cancelled")
+
+	errProjectsConfigMismatch = errors.New(
+		"projects with different kinds, components or custom choices can not share a change",
+	)
 
 	// prompt disabled
 	errProjectMissingPromptDisabled   = errors.New("project missing and prompt is disabled")
@@ -35,12 +45,23 @@ type Prompts struct {
 	Config           *Config
 	StdinReader      io.Reader
 	KindConfig       *KindConfig
+	ComponentConfig  *ComponentConfig
 	BodyEditor       bool
 	EditorCmdBuilder func(string) (EditorRunner, error)
 	TimeNow          TimeNow
 
 	// Enabled checks to make sure our terminal supports prompts
 	Enabled bool
+	// Review shows the resulting changes for confirmation before returning them.
+	// Review is skipped if prompts are not enabled.
+	Review bool
+	// TemplateCache is used to display the fragment paths when reviewing changes.
+	TemplateCache *TemplateCache
+	// Defaults pre-fill the prompts, such as when editing an existing change.
+	Defaults *Change
+
+	// rootConfig is the config before any project overrides are applied.
+	rootConfig *Config
 
 	// Values can be submitted from the environment or shell arguments.
 	Projects  []string
@@ -54,23 +75,26 @@ type Prompts struct {
 // returning all changes as prompts are answered.
 // A change relates to a single project and single change, so if the change
 // affects multiple projects we will return multiple changes.
+// If review is enabled, the resulting changes are shown before returning and
+// the user can confirm, edit a value or cancel the change.
 func (p *Prompts) BuildChanges() ([]*Change, error) {
-	err := p.validateArguments()
+	// Projects are selected first as they can override the other config options.
+	err := p.projects(p.defaults())
 	if err != nil {
 		return nil, err
 	}
 
-	err = p.projects()
+	err = p.validateArguments()
 	if err != nil {
 		return nil, err
 	}
 
-	err = p.component()
+	err = p.component(p.defaults())
 	if err != nil {
 		return nil, err
 	}
 
-	err = p.kind()
+	err = p.kind(p.defaults())
 	if err != nil {
 		return nil, err
 	}
@@ -85,20 +109,32 @@ func (p *Prompts) BuildChanges() ([]*Change, error) {
 		return nil, err
 	}
 
-	envs := p.Config.EnvVars()
+	for {
+		changes, err := p.buildChanges()
+		if err != nil {
+			return nil, err
+		}
 
-	// If we don't have projects enabled, just create a single change.
-	if len(p.Projects) == 0 {
-		change := &Change{
-			Component: p.Component,
-			Kind:      p.Kind,
-			Body:      p.Body,
-			Time:      p.TimeNow(),
-			Custom:    p.Customs,
-			Env:       envs,
+		if !p.Enabled || !p.Review {
+			return changes, nil
 		}
 
-		err = change.PostProcess(p.Config, p.KindConfig)
+		done, err := p.review(changes)
+		if err != nil {
+			return nil, err
+		}
+
+		if done {
+			return changes, nil
+		}
+	}
+}
+
+// buildChanges creates the changes from the current prompt values.
+func (p *Prompts) buildChanges() ([]*Change, error) {
+	// If we don't have projects enabled, just create a single change.
+	if len(p.Projects) == 0 {
+		change, err := p.buildChange("")
 		if err != nil {
 			return nil, err
 		}
@@ -106,29 +142,62 @@ func (p *Prompts) BuildChanges() ([]*Change, error) {
 		return []*Change{change}, nil
 	}
 
+	if p.Config.SharedProjectFragments && len(p.Projects) > 1 {
+		return p.buildSharedChange()
+	}
+
 	changes := make([]*Change, len(p.Projects))
 	for i := range changes {
 		// Err is already validated when getting the project above.
 		projConfig, _ := p.Config.Project(p.Projects[i])
-		changes[i] = &Change{
-			Project:   projConfig.Key,
-			Component: p.Component,
-			Kind:      p.Kind,
-			Body:      p.Body,
-			Time:      p.TimeNow(),
-			Custom:    p.Customs,
-			Env:       envs,
-		}
 
-		err = changes[i].PostProcess(p.Config, p.KindConfig)
+		change, err := p.buildChange(projConfig.Key)
 		if err != nil {
 			return nil, err
 		}
+
+		changes[i] = change
 	}
 
 	return changes, nil
 }
 
+// buildSharedChange creates a single change shared by all the selected projects.
+func (p *Prompts) buildSharedChange() ([]*Change, error) {
+	change, err := p.buildChange("")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, proj := range p.Projects {
+		// Err is already validated when getting the project above.
+		projConfig, _ := p.Config.Project(proj)
+		change.Projects = append(change.Projects, projConfig.Key)
+	}
+
+	return []*Change{change}, nil
+}
+
+func (p *Prompts) buildChange(project string) (*Change, error) {
+	change := &Change{
+		Project:   project,
+		Component: p.Component,
+		Kind:      p.Kind,
+		Body:      p.Body,
+		Time:      p.TimeNow(),
+		// Clone our customs so post processing does not leak into other changes.
+		Custom: maps.Clone(p.Customs),
+		Env:    p.Config.EnvVars(),
+	}
+
+	err := change.PostProcess(p.Config, p.KindConfig, p.ComponentConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
 // validateArguments will check the initial state of a change against the config
 // and return an error if anything is invalid
 func (p *Prompts) validateArguments() error {
@@ -156,6 +225,15 @@ func (p *Prompts) validateArguments() error {
 		configuredCustoms = append(configuredCustoms, p.Config.CustomChoices...)
 	}
 
+	if len(p.Component) > 0 {
+		cc := p.Config.ComponentFromKeyOrLabel(p.Component)
+		if cc == nil {
+			return fmt.Errorf("%w: %s", errInvalidComponent, p.Component)
+		}
+
+		configuredCustoms = append(configuredCustoms, cc.AdditionalChoices...)
+	}
+
 	// make sure no custom values are assigned that do not exist
 	foundCustoms := map[string]struct{}{}
 
@@ -184,7 +262,8 @@ func (p *Prompts) validateArguments() error {
 	return nil
 }
 
-func (p *Prompts) projects() error {
+// projects asks for the projects if none are set, pre-selecting the projects of preselect.
+func (p *Prompts) projects(preselect Change) error {
 	if len(p.Config.Projects) == 0 {
 		return nil
 	}
@@ -196,11 +275,20 @@ func (p *Prompts) projects() error {
 
 		var err error
 
+		selected := make([]int, 0)
+
+		for i, pc := range p.Config.Projects {
+			if preselect.HasProject(pc.Key) {
+				selected = append(selected, i)
+			}
+		}
+
 		projs, err := prompt.New().Ask("Projects").
 			MultiChoose(
 				p.Config.ProjectLabels(),
 				multichoose.WithHelp(true),
 				multichoose.WithTeaProgramOpts(tea.WithInput(p.StdinReader)),
+				multichoose.WithDefaultIndexes(0, selected),
 			)
 		if err != nil {
 			return err
@@ -213,18 +301,46 @@ func (p *Prompts) projects() error {
 		p.Projects = projs
 	}
 
-	// Quickly validate the project exists and lines up before moving on.
+	return p.useProjectsConfig()
+}
+
+// useProjectsConfig validates the selected projects and uses their effective config for
+// the remaining prompts, as projects can override kinds, components and custom choices.
+// Projects sharing a change must have the same kinds, components and custom choices.
+func (p *Prompts) useProjectsConfig() error {
+	if p.rootConfig == nil {
+		p.rootConfig = p.Config
+	}
+
+	var effective *Config
+
 	for _, proj := range p.Projects {
-		_, err := p.Config.Project(proj)
+		projectConfig, err := p.rootConfig.ForProject(proj)
 		if err != nil {
 			return fmt.Errorf("%w: %s", err, proj)
 		}
+
+		if effective != nil && !sameChoices(effective, projectConfig) {
+			return fmt.Errorf("%w: %s", errProjectsConfigMismatch, strings.Join(p.Projects, ", "))
+		}
+
+		effective = projectConfig
 	}
 
+	p.Config = effective
+
 	return nil
 }
 
-func (p *Prompts) component() error {
+// sameChoices returns whether two configs share the same kinds, components and custom choices.
+func sameChoices(a, b *Config) bool {
+	return reflect.DeepEqual(a.Kinds, b.Kinds) &&
+		reflect.DeepEqual(a.Components, b.Components) &&
+		reflect.DeepEqual(a.CustomChoices, b.CustomChoices)
+}
+
+// component asks for the component if none is set, pre-selecting the component of preselect.
+func (p *Prompts) component(preselect Change) error {
 	if len(p.Config.Components) == 0 {
 		return nil
 	}
@@ -234,13 +350,25 @@ func (p *Prompts) component() error {
 			return errComponentMissingPromptDisabled
 		}
 
-		var err error
+		defaultIndex := 0
+		choices := make([]choose.Choice, len(p.Config.Components))
 
-		comp, err := Custom{
-			Type:        CustomEnum,
-			Label:       "Component",
-			EnumOptions: p.Config.Components,
-		}.AskPrompt(p.StdinReader)
+		for i, cc := range p.Config.Components {
+			choices[i] = choose.Choice{Text: cc.Label, Note: cc.Description}
+
+			if preselect.Component != "" && cc.KeyOrLabel() == preselect.Component {
+				defaultIndex = i
+			}
+		}
+
+		comp, err := prompt.New().Ask("Component").
+			AdvancedChoose(
+				choices,
+				choose.WithHelp(true),
+				choose.WithTeaProgramOpts(tea.WithInput(p.StdinReader)),
+				choose.WithTheme(ThemeScroll),
+				choose.WithDefaultIndex(defaultIndex),
+			)
 		if err != nil {
 			return err
 		}
@@ -248,14 +376,19 @@ func (p *Prompts) component() error {
 		p.Component = comp
 	}
 
-	if !slices.Contains(p.Config.Components, p.Component) {
+	cc := p.Config.ComponentFromKeyOrLabel(p.Component)
+	if cc == nil {
 		return fmt.Errorf("%w: %s", errInvalidComponent, p.Component)
 	}
 
+	p.ComponentConfig = cc
+	p.Component = cc.KeyOrLabel()
+
 	return nil
 }
 
-func (p *Prompts) kind() error {
+// kind asks for the kind if none is set, pre-selecting the kind of preselect.
+func (p *Prompts) kind(preselect Change) error {
 	if len(p.Config.Kinds) == 0 {
 		return nil
 	}
@@ -265,16 +398,22 @@ func (p *Prompts) kind() error {
 			return errKindMissingPromptDisabled
 		}
 
+		defaultLabel := ""
 		kindLabels := make([]string, len(p.Config.Kinds))
+
 		for i, kc := range p.Config.Kinds {
 			kindLabels[i] = kc.Label
+
+			if preselect.Kind != "" && kc.KeyOrLabel() == preselect.Kind {
+				defaultLabel = kc.Label
+			}
 		}
 
 		kind, err := Custom{
 			Type:        CustomEnum,
 			Label:       "Kind",
 			EnumOptions: kindLabels,
-		}.AskPrompt(p.StdinReader)
+		}.AskPromptWithDefault(p.StdinReader, defaultLabel)
 		if err != nil {
 			return err
 		}
@@ -285,7 +424,7 @@ func (p *Prompts) kind() error {
 	for i := range p.Config.Kinds {
 		kindConfig := &p.Config.Kinds[i]
 
-		if kindConfig.Label == p.Kind || kindConfig.Key == p.Kind {
+		if kindConfig.Label == p.Kind || kindConfig.Key == p.Kind || slices.Contains(kindConfig.Aliases, p.Kind) {
 			p.KindConfig = kindConfig
 			p.Kind = kindConfig.KeyOrLabel()
 
@@ -306,41 +445,53 @@ func (p *Prompts) body() error {
 			return errBodyMissingPromptDisabled
 		}
 
-		if p.BodyEditor {
-			file, err := createTempFile(runtime.GOOS, p.Config.VersionExt)
-			if err != nil {
-				return err
-			}
+		return p.askBody(p.defaults().Body)
+	}
 
-			runner, err := p.EditorCmdBuilder(file)
-			if err != nil {
-				return err
-			}
+	if p.expectsBody() && len(p.Body) > 0 {
+		return p.Config.Body.Validate(p.Body)
+	}
 
-			p.Body, err = getBodyTextWithEditor(runner, file)
+	return nil
+}
 
+// askBody asks for the body with the editor or a prompt, pre-filled with a value.
+func (p *Prompts) askBody(value string) error {
+	if p.BodyEditor {
+		file, err := createTempFile(runtime.GOOS, p.Config.VersionExt, value)
+		if err != nil {
 			return err
-		} else {
-			bodyCustom := p.Config.Body.CreateCustom()
-
-			body, err := bodyCustom.AskPrompt(p.StdinReader)
-			if err != nil {
-				return err
-			}
-
-			p.Body = body
+		}
 
+		runner, err := p.EditorCmdBuilder(file)
+		if err != nil {
 			return err
 		}
+
+		p.Body, err = getBodyTextWithEditor(runner, file)
+
+		return err
 	}
 
-	if p.expectsBody() && len(p.Body) > 0 {
-		return p.Config.Body.Validate(p.Body)
+	body, err := p.Config.Body.CreateCustom().AskPromptWithDefault(p.StdinReader, value)
+	if err != nil {
+		return err
 	}
 
+	p.Body = body
+
 	return nil
 }
 
+// defaults returns the default values for our prompts, or an empty change if none are set.
+func (p *Prompts) defaults() Change {
+	if p.Defaults == nil {
+		return Change{}
+	}
+
+	return *p.Defaults
+}
+
 func (p *Prompts) expectsNoBody() bool {
 	return p.KindConfig != nil && p.KindConfig.SkipBody
 }
@@ -349,7 +500,8 @@ func (p *Prompts) expectsBody() bool {
 	return p.KindConfig == nil || !p.KindConfig.SkipBody
 }
 
-func (p *Prompts) userChoices() error {
+// customChoices returns the custom choices expected for the selected kind.
+func (p *Prompts) customChoices() []Custom {
 	userChoices := make([]Custom, 0)
 
 	if p.KindConfig == nil || !p.KindConfig.SkipGlobalChoices {
@@ -360,6 +512,16 @@ func (p *Prompts) userChoices() error {
 		userChoices = append(userChoices, p.KindConfig.AdditionalChoices...)
 	}
 
+	if p.ComponentConfig != nil {
+		userChoices = append(userChoices, p.ComponentConfig.AdditionalChoices...)
+	}
+
+	return userChoices
+}
+
+func (p *Prompts) userChoices() error {
+	userChoices := p.customChoices()
+
 	// custom map may be nil, which is fine if we have no choices
 	// otherwise we need to initialize it
 	if len(userChoices) > 0 && p.Customs == nil {
@@ -382,7 +544,7 @@ func (p *Prompts) userChoices() error {
 
 		var err error
 
-		p.Customs[custom.Key], err = custom.AskPrompt(p.StdinReader)
+		p.Customs[custom.Key], err = custom.AskPromptWithDefault(p.StdinReader, p.defaults().Custom[custom.Key])
 		if err != nil {
 			return err
 		}
@@ -390,3 +552,270 @@ func (p *Prompts) userChoices() error {
 
 	return nil
 }
+
+const (
+	reviewConfirm = "Confirm"
+	reviewCancel  = "Cancel"
+)
+
+// reviewOption is a value that can be edited when reviewing changes.
+type reviewOption struct {
+	label string
+	edit  func() error
+}
+
+// review displays the changes and the files they will be saved to, returning
+// true once the user confirms them.
+// If a value is edited, false is returned so the changes can be rebuilt.
+func (p *Prompts) review(changes []*Change) (bool, error) {
+	summary, err := p.reviewSummary(changes)
+	if err != nil {
+		return false, err
+	}
+
+	options := p.reviewOptions()
+	labels := make([]string, 0, len(options)+2)
+	labels = append(labels, reviewConfirm)
+
+	for _, opt := range options {
+		labels = append(labels, opt.label)
+	}
+
+	labels = append(labels, reviewCancel)
+
+	choice, err := Custom{
+		Type:        CustomEnum,
+		Label:       summary,
+		EnumOptions: labels,
+	}.AskPrompt(p.StdinReader)
+	if err != nil {
+		return false, err
+	}
+
+	switch choice {
+	case reviewConfirm:
+		return true, nil
+	case reviewCancel:
+		return false, errChangeCancelled
+	}
+
+	for _, opt := range options {
+		if opt.label == choice {
+			return false, opt.edit()
+		}
+	}
+
+	return false, fmt.Errorf("%w: %s", errInvalidEnum, choice)
+}
+
+func (p *Prompts) reviewSummary(changes []*Change) (string, error) {
+	cache := p.TemplateCache
+	if cache == nil {
+		cache = NewTemplateCache()
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Review new change\n")
+
+	paths := make([]string, 0, len(changes))
+
+	for _, change := range changes {
+		path, err := p.Config.FragmentPath(cache, change)
+		if err != nil {
+			return "", err
+		}
+
+		path, err = UniquePath(path, paths)
+		if err != nil {
+			return "", err
+		}
+
+		paths = append(paths, path)
+
+		sb.WriteString("\n# " + path + "\n")
+
+		_, err = change.WriteFormat(&sb, filepath.Ext(path))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sb.WriteString("\nConfirm, edit or cancel the change")
+
+	return sb.String(), nil
+}
+
+func (p *Prompts) reviewOptions() []reviewOption {
+	options := make([]reviewOption, 0)
+
+	if len(p.Config.Projects) > 0 {
+		options = append(options, reviewOption{
+			label: "Edit projects",
+			edit:  p.editProjects,
+		})
+	}
+
+	if len(p.Config.Components) > 0 {
+		options = append(options, reviewOption{
+			label: "Edit component",
+			edit:  p.editComponent,
+		})
+	}
+
+	if len(p.Config.Kinds) > 0 {
+		options = append(options, reviewOption{
+			label: "Edit kind",
+			edit:  p.editKind,
+		})
+	}
+
+	if p.expectsBody() {
+		options = append(options, reviewOption{
+			label: "Edit body",
+			edit: func() error {
+				// keep the current body so small fixes do not require typing it again
+				return p.askBody(p.Body)
+			},
+		})
+	}
+
+	for _, custom := range p.customChoices() {
+		options = append(options, reviewOption{
+			label: "Edit " + custom.DisplayLabel(),
+			edit: func() error {
+				value, err := custom.AskPromptWithDefault(p.StdinReader, p.Customs[custom.Key])
+				if err != nil {
+					return err
+				}
+
+				p.Customs[custom.Key] = value
+
+				return nil
+			},
+		})
+	}
+
+	return options
+}
+
+// editProjects asks for the projects again, if the new projects use different kinds,
+// components or custom choices, those are asked for again as well.
+func (p *Prompts) editProjects() error {
+	previous := p.Config
+	current := p.current()
+	p.Projects = nil
+
+	err := p.projects(current)
+	if err != nil {
+		return err
+	}
+
+	if sameChoices(previous, p.Config) {
+		return nil
+	}
+
+	p.Component = ""
+	p.ComponentConfig = nil
+	p.Kind = ""
+	p.KindConfig = nil
+	p.Body = ""
+	p.Customs = nil
+
+	err = p.component(p.defaults())
+	if err != nil {
+		return err
+	}
+
+	err = p.kind(p.defaults())
+	if err != nil {
+		return err
+	}
+
+	err = p.body()
+	if err != nil {
+		return err
+	}
+
+	return p.userChoices()
+}
+
+// current returns the projects, component and kind selected so far, used to
+// pre-select them when asking again during review.
+func (p *Prompts) current() Change {
+	config := p.rootConfig
+	if config == nil {
+		config = p.Config
+	}
+
+	projects := make([]string, 0, len(p.Projects))
+
+	for _, proj := range p.Projects {
+		// projects can be selected by label, defaults are matched by key
+		pc, err := config.Project(proj)
+		if err == nil {
+			proj = pc.Key
+		}
+
+		projects = append(projects, proj)
+	}
+
+	return Change{
+		Projects:  projects,
+		Component: p.Component,
+		Kind:      p.Kind,
+	}
+}
+
+// editComponent asks for the component again, removing any custom values that no
+// longer apply and asking for missing ones.
+func (p *Prompts) editComponent() error {
+	current := p.current()
+	p.Component = ""
+	p.ComponentConfig = nil
+
+	err := p.component(current)
+	if err != nil {
+		return err
+	}
+
+	p.removeUnexpectedCustoms()
+
+	return p.userChoices()
+}
+
+// removeUnexpectedCustoms removes custom values not expected by the selected kind or component.
+func (p *Prompts) removeUnexpectedCustoms() {
+	choices := p.customChoices()
+	for key := range p.Customs {
+		if !slices.ContainsFunc(choices, func(c Custom) bool { return c.Key == key }) {
+			delete(p.Customs, key)
+		}
+	}
+}
+
+// editKind asks for the kind again, as the kind can change which body and custom
+// values are expected we remove any that no longer apply and ask for missing ones.
+func (p *Prompts) editKind() error {
+	current := p.current()
+	p.Kind = ""
+	p.KindConfig = nil
+
+	err := p.kind(current)
+	if err != nil {
+		return err
+	}
+
+	p.removeUnexpectedCustoms()
+
+	if p.expectsNoBody() {
+		p.Body = ""
+	}
+
+	err = p.body()
+	if err != nil {
+		return err
+	}
+
+	return p.userChoices()
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"os"
 	"testing"
 	"time"
 
@@ -95,13 +96,168 @@ func TestAskPromptsForBodyWithProjectErrBadProject(t *testing.T) {
 	then.Err(t, errProjectNotFound, err)
 }
 
+func TestBuildChangesResolvesAliases(t *testing.T) {
+	config := &Config{
+		Projects: []ProjectConfig{
+			{Label: "Client", Key: "client", Aliases: []string{"web"}},
+		},
+		Components: []ComponentConfig{{Label: "cli", Aliases: []string{"command"}}},
+		Kinds: []KindConfig{
+			{Label: "added", Aliases: []string{"feature"}},
+		},
+	}
+
+	prompts := &Prompts{
+		Config:    config,
+		TimeNow:   specificTimeNow,
+		Projects:  []string{"web"},
+		Component: "command",
+		Kind:      "feature",
+		Body:      "body",
+		Enabled:   false,
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "client", changes[0].Project)
+	then.Equals(t, "cli", changes[0].Component)
+	then.Equals(t, "added", changes[0].Kind)
+}
+
+func TestBuildChangesWithComponentConfig(t *testing.T) {
+	config := &Config{
+		Components: []ComponentConfig{
+			{
+				Key:   "cli",
+				Label: "CLI",
+				AdditionalChoices: []Custom{
+					{Key: "Command", Type: CustomString},
+				},
+				Post: []PostProcessConfig{
+					{Key: "Team", Value: "Tools"},
+				},
+			},
+		},
+	}
+
+	prompts := &Prompts{
+		Config:    config,
+		TimeNow:   specificTimeNow,
+		Component: "CLI",
+		Body:      "body",
+		Customs:   map[string]string{"Command": "new"},
+		Enabled:   false,
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "cli", changes[0].Component)
+	then.Equals(t, "new", changes[0].Custom["Command"])
+	then.Equals(t, "Tools", changes[0].Custom["Team"])
+}
+
+func TestErrorBuildChangesComponentChoiceWithoutComponent(t *testing.T) {
+	config := &Config{
+		Components: []ComponentConfig{
+			{Label: "cli", AdditionalChoices: []Custom{{Key: "Command", Type: CustomString}}},
+			{Label: "web"},
+		},
+	}
+
+	prompts := &Prompts{
+		Config:    config,
+		TimeNow:   specificTimeNow,
+		Component: "web",
+		Body:      "body",
+		Customs:   map[string]string{"Command": "new"},
+		Enabled:   false,
+	}
+
+	_, err := prompts.BuildChanges()
+	then.Err(t, errCustomProvidedNotConfigured, err)
+}
+
+func TestBuildChangesUsesProjectConfig(t *testing.T) {
+	config := &Config{
+		Kinds: []KindConfig{{Label: "added"}},
+		Projects: []ProjectConfig{
+			{Label: "CLI", Key: "cli", Kinds: []KindConfig{{Label: "Command"}}},
+			{Label: "Docs", Key: "docs", Kinds: []KindConfig{{Label: "Command"}}},
+			{Label: "SDK", Key: "sdk"},
+		},
+	}
+
+	prompts := &Prompts{
+		Config:   config,
+		TimeNow:  specificTimeNow,
+		Projects: []string{"cli", "docs"},
+		Kind:     "Command",
+		Body:     "body",
+		Enabled:  false,
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 2, changes)
+	then.Equals(t, "Command", changes[0].Kind)
+	then.Equals(t, "docs", changes[1].Project)
+}
+
+func TestErrorBuildChangesProjectsWithDifferentConfigs(t *testing.T) {
+	config := &Config{
+		Kinds: []KindConfig{{Label: "added"}},
+		Projects: []ProjectConfig{
+			{Label: "CLI", Key: "cli", Kinds: []KindConfig{{Label: "Command"}}},
+			{Label: "SDK", Key: "sdk"},
+		},
+	}
+
+	prompts := &Prompts{
+		Config:   config,
+		TimeNow:  specificTimeNow,
+		Projects: []string{"cli", "sdk"},
+		Kind:     "added",
+		Body:     "body",
+		Enabled:  false,
+	}
+
+	_, err := prompts.BuildChanges()
+	then.Err(t, errProjectsConfigMismatch, err)
+}
+
+func TestBuildChangesSharedProjectFragment(t *testing.T) {
+	config := &Config{
+		SharedProjectFragments: true,
+		Projects: []ProjectConfig{
+			{Label: "CLI", Key: "cli"},
+			{Label: "SDK", Key: "sdk"},
+		},
+	}
+
+	prompts := &Prompts{
+		Config:   config,
+		TimeNow:  specificTimeNow,
+		Projects: []string{"CLI", "sdk"},
+		Body:     "body",
+		Enabled:  false,
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "", changes[0].Project)
+	then.SliceEquals(t, []string{"cli", "sdk"}, changes[0].Projects)
+}
+
 func TestAskPromptsFailIfDisabled(t *testing.T) {
 	config := &Config{
 		Projects: []ProjectConfig{
 			{Label: "Client", Key: "client"},
 			{Label: "Other", Key: "other"},
 		},
-		Components: []string{"cli", "tests", "utils"},
+		Components: []ComponentConfig{{Label: "cli"}, {Label: "tests"}, {Label: "utils"}},
 		Kinds: []KindConfig{
 			{Label: "added"},
 			{Label: "changed"},
@@ -245,7 +401,7 @@ func TestAskComponentKindBody(t *testing.T) {
 	)
 
 	config := &Config{
-		Components: []string{"cli", "tests", "utils"},
+		Components: []ComponentConfig{{Label: "cli"}, {Label: "tests"}, {Label: "utils"}},
 		Kinds: []KindConfig{
 			{Label: "added"},
 			{Label: "changed"},
@@ -594,7 +750,7 @@ func TestErrorInvalidComponent(t *testing.T) {
 	)
 
 	config := &Config{
-		Components: []string{"a", "b"},
+		Components: []ComponentConfig{{Label: "a"}, {Label: "b"}},
 	}
 	prompts := &Prompts{
 		Config:      config,
@@ -699,7 +855,7 @@ func TestErrorBadKindLabel(t *testing.T) {
 func TestErrorBadComponentInput(t *testing.T) {
 	reader, _ := then.WithReadWritePipe(t)
 	config := &Config{
-		Components: []string{"a", "b", "c"},
+		Components: []ComponentConfig{{Label: "a"}, {Label: "b"}, {Label: "c"}},
 	}
 	prompts := &Prompts{
 		Config:      config,
@@ -843,7 +999,7 @@ func TestSkipPromptForComponentIfSet(t *testing.T) {
 	)
 
 	config := &Config{
-		Components: []string{"a", "b"},
+		Components: []ComponentConfig{{Label: "a"}, {Label: "b"}},
 	}
 	prompts := &Prompts{
 		Config:      config,
@@ -1040,3 +1196,308 @@ func TestGetBodyTxtWithEditorUnableToCreateCmd(t *testing.T) {
 	_, err := prompts.BuildChanges()
 	then.NotNil(t, err)
 }
+
+func TestAskPromptsReviewEditBody(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		[]byte("body typo"),
+		[]byte{13},
+		// review: move to "Edit body"
+		[]byte{106, 13},
+		[]byte("body fixed"),
+		[]byte{13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Time.Format \"20060102\"}}",
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "body fixed", changes[0].Body)
+}
+
+func TestAskPromptsReviewEditBodyWithEditorKeepsBody(t *testing.T) {
+	then.WithTempDir(t)
+
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		// review: move to "Edit body"
+		[]byte{106, 13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	var prefills []string
+
+	bodies := []string{"body typo", "body fixed"}
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Time.Format \"20060102\"}}",
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		BodyEditor:  true,
+		EditorCmdBuilder: func(filename string) (EditorRunner, error) {
+			contents, err := os.ReadFile(filename)
+			then.Nil(t, err)
+
+			prefills = append(prefills, string(contents))
+			body := bodies[len(prefills)-1]
+
+			return &dummyEditorRunner{filename: filename, body: []byte(body), t: t}, nil
+		},
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "body fixed", changes[0].Body)
+	then.SliceEquals(t, []string{"", "body typo"}, prefills)
+}
+
+func TestAskPromptsReviewEditCustomKeepsValue(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		[]byte("me"),
+		[]byte{13},
+		// review: move to "Edit Author" and keep the current value
+		[]byte{106, 106, 13},
+		[]byte{13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Time.Format \"20060102\"}}",
+		CustomChoices: []Custom{
+			{Key: "Author", Type: CustomString},
+		},
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		Body:        "a body",
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "me", changes[0].Custom["Author"])
+}
+
+func TestAskPromptsReviewEditKindRemovesCustoms(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		// review: move to "Edit kind" and select "removed"
+		[]byte{106, 13},
+		[]byte{106, 13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Kind}}",
+		Kinds: []KindConfig{
+			{
+				Label: "added",
+				AdditionalChoices: []Custom{
+					{Key: "Issue", Type: CustomInt},
+				},
+			},
+			{Label: "removed", SkipBody: true},
+		},
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		Kind:        "added",
+		Body:        "some body",
+		Customs:     map[string]string{"Issue": "12"},
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "removed", changes[0].Kind)
+	then.Equals(t, "", changes[0].Body)
+	then.MapLen(t, 0, changes[0].Custom)
+}
+
+func TestAskPromptsReviewEditProjectsKeepsSelection(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		// review: move to "Edit projects" and keep the current projects
+		[]byte{106, 13},
+		[]byte{13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Project}}",
+		Projects: []ProjectConfig{
+			{Label: "Client", Key: "client"},
+			{Label: "Other", Key: "other"},
+		},
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		Projects:    []string{"Other"},
+		Body:        "a body",
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "other", changes[0].Project)
+}
+
+func TestAskPromptsReviewEditComponentKeepsSelection(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		// review: move to "Edit component" and keep the current component
+		[]byte{106, 13},
+		[]byte{13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Component}}",
+		Components:         []ComponentConfig{{Label: "cli"}, {Label: "tests"}, {Label: "utils"}},
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		Component:   "tests",
+		Body:        "a body",
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "tests", changes[0].Component)
+}
+
+func TestAskPromptsReviewEditKindKeepsSelection(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		// review: move to "Edit kind" and keep the current kind
+		[]byte{106, 13},
+		[]byte{13},
+		// review: confirm
+		[]byte{13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Kind}}",
+		Kinds: []KindConfig{
+			{Label: "added"},
+			{Label: "removed"},
+			{Label: "fixed"},
+		},
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		Kind:        "fixed",
+		Body:        "a body",
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "fixed", changes[0].Kind)
+}
+
+func TestAskPromptsReviewCancel(t *testing.T) {
+	reader, writer := then.WithReadWritePipe(t)
+	then.DelayWrite(
+		t, writer,
+		// review: move past "Edit body" to "Cancel"
+		[]byte{106, 106, 13},
+	)
+
+	config := &Config{
+		ChangesDir:         "changes",
+		UnreleasedDir:      "unreleased",
+		FragmentFileFormat: "{{.Body}}",
+	}
+	prompts := &Prompts{
+		Config:      config,
+		StdinReader: reader,
+		TimeNow:     specificTimeNow,
+		Enabled:     true,
+		Review:      true,
+		Body:        "some body",
+	}
+
+	_, err := prompts.BuildChanges()
+	then.Err(t, errChangeCancelled, err)
+}
+
+func TestAskPromptsReviewSkippedWhenDisabled(t *testing.T) {
+	config := &Config{}
+	prompts := &Prompts{
+		Config:  config,
+		TimeNow: specificTimeNow,
+		Enabled: false,
+		Review:  true,
+		Body:    "some body",
+	}
+
+	changes, err := prompts.BuildChanges()
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+}
@@ -19,6 +19,10 @@ type BatchData struct {
 	VersionNoPrefix string
 	// Previous released version
 	PreviousVersion string
+	// Next version predicted using auto, the same as version when batching.
+	// When merging unreleased changes, version is the unreleased header and this is the
+	// version batch auto would release, or empty if no release is needed.
+	NextVersion string
 	// Major value of the version
 	Major int
 	// Minor value of the version
@@ -45,6 +49,23 @@ type ComponentData struct {
 	Env map[string]string
 }
 
+// Combined release data stores data related to writing a release in the combined changelog.
+type CombinedReleaseData struct {
+	// Time of the release, from the git commit that added the version file
+	Time time.Time
+	// Label of the project
+	Project string
+	// Key of the project
+	ProjectKey string
+	// Version of the release, will include "v" prefix if used
+	Version string
+	// Version of the release without the "v" prefix if used
+	VersionNoPrefix string
+	// Env vars configured by the system.
+	// See [envPrefix](#config-envprefix) for configuration.
+	Env map[string]string
+}
+
 // Kind data stores data related to writing kind headers.
 type KindData struct {
 	// Name of the kind
@@ -54,6 +75,47 @@ type KindData struct {
 	Env map[string]string
 }
 
+// Changelog data stores data related to writing the changelog header and footer when merging.
+type ChangelogData struct {
+	// Key of the project, if using projects
+	Project string
+	// Versions included in the changelog, in the order they are merged
+	Versions []ChangelogVersion
+	// Env vars configured by the system.
+	// See [envPrefix](#config-envprefix) for configuration.
+	Env map[string]string
+}
+
+// Changelog version stores data related to a single version in the merged changelog.
+type ChangelogVersion struct {
+	// Version of the release, will include "v" prefix if used
+	Version string
+	// Version of the release without the "v" prefix if used
+	VersionNoPrefix string
+	// Version released before this one, empty for the first release
+	PreviousVersion string
+	// Version released after this one, empty for the latest release
+	NextVersion string
+	// Anchor linking to the version in the changelog.
+	// This is the anchor GitHub generates for the first heading of the version file,
+	// such as v120---2024-01-02 for "## v1.2.0 - 2024-01-02", or v120 for v1.2.0
+	// when [changelogVersionAnchors](#config-changelogversionanchors) is enabled.
+	Anchor string
+}
+
+// Archive data stores data related to naming and linking changelog archives.
+type ArchiveData struct {
+	// Key of the project, if using projects
+	Project string
+	// Major version of every release in the archive
+	Major int
+	// Path of the archive relative to the changelog, empty when rendering the path format
+	Path string
+	// Env vars configured by the system.
+	// See [envPrefix](#config-envprefix) for configuration.
+	Env map[string]string
+}
+
 // Template cache handles running all the templates for change fragments.
 // Included options include the default [go template](https://golang.org/pkg/text/template/)
 // and [sprig functions](https://masterminds.github.io/sprig/) for formatting.
@@ -9,7 +9,9 @@ import (
 	"io/fs"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -30,7 +32,11 @@ var (
 	ErrBadVersionOrPart      = errors.New("part string is not a supported version or version increment")
 	ErrMissingAutoLevel      = errors.New("kind config missing auto level value for auto bumping")
 	ErrNoChangesFoundForAuto = errors.New("no unreleased changes found for automatic bumping")
+	ErrNoReleaseNeeded       = errors.New("no release needed, only changes with an auto level of none found")
 	ErrKindNotFound          = errors.New("kind not found but configuration expects one")
+	ErrComponentNotFound     = errors.New("component not found in configuration")
+	ErrFragmentExists        = errors.New("fragment file already exists")
+	ErrUnknownFragmentFile   = errors.New("file is not a supported change fragment")
 )
 
 var (
@@ -49,35 +55,93 @@ func AppendFile(rootFile io.Writer, path string) error {
 	return err
 }
 
+// GetAllVersions returns all released versions from the configured version source,
+// sorted newest first.
 func GetAllVersions(
 	config *Config,
 	skipPrereleases bool,
 	projectKey string,
+) ([]*semver.Version, error) {
+	switch config.VersionSource {
+	case "", VersionSourceFiles:
+		return GetFileVersions(config, skipPrereleases, projectKey)
+	case VersionSourceTags:
+		return GetTagVersions(config, skipPrereleases, projectKey)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrVersionSource, config.VersionSource)
+}
+
+// GetFileVersions returns all versions with a version file, sorted newest first.
+// Version files are matched using the version file format.
+func GetFileVersions(
+	config *Config,
+	skipPrereleases bool,
+	projectKey string,
 ) ([]*semver.Version, error) {
 	allVersions := make([]*semver.Version, 0)
 
-	versionsPath := config.ChangesDir
-	if len(projectKey) > 0 {
-		versionsPath = filepath.Join(versionsPath, projectKey)
+	vff, err := newVersionFileFormat(config)
+	if err != nil {
+		return allVersions, err
 	}
 
-	fileInfos, err := os.ReadDir(versionsPath)
-	if err != nil && errors.Is(err, fs.ErrNotExist) {
-		return allVersions, nil
-	}
+	versionsPath := filepath.Join(config.ChangesDir, projectKey)
 
+	matches, err := filepath.Glob(filepath.Join(versionsPath, vff.pattern()))
 	if err != nil {
 		return allVersions, fmt.Errorf("reading files from '%s': %w", versionsPath, err)
 	}
 
-	for _, file := range fileInfos {
-		if file.Name() == config.HeaderPath || file.IsDir() {
+	for _, match := range matches {
+		relPath, err := filepath.Rel(versionsPath, match)
+		if err != nil {
+			return allVersions, err
+		}
+
+		v, ok := vff.parse(relPath)
+		if !ok {
+			continue
+		}
+
+		if skipPrereleases && v.Prerelease() != "" {
 			continue
 		}
 
-		versionString := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		allVersions = append(allVersions, v)
+	}
+
+	sort.Sort(sort.Reverse(semver.Collection(allVersions)))
+
+	return allVersions, nil
+}
+
+// GetTagVersions returns all versions tagged in git, sorted newest first.
+// When a project key is provided, only tags prefixed with the key and projects version
+// separator are included.
+func GetTagVersions(
+	config *Config,
+	skipPrereleases bool,
+	projectKey string,
+) ([]*semver.Version, error) {
+	out, err := exec.CommandContext(context.Background(), "git", "tag", "--list").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing git tags: %w", err)
+	}
+
+	prefix := ""
+	if len(projectKey) > 0 {
+		prefix = projectKey + config.ProjectsVersionSeparator
+	}
+
+	allVersions := make([]*semver.Version, 0)
 
-		v, err := semver.NewVersion(versionString)
+	for _, tag := range strings.Split(string(out), "\n") {
+		if tag == "" || !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+
+		v, err := semver.NewVersion(strings.TrimPrefix(tag, prefix))
 		if err != nil {
 			continue
 		}
@@ -120,53 +184,39 @@ func ValidBumpLevel(level string) bool {
 }
 
 func HighestAutoLevel(config *Config, cache *TemplateCache, allChanges []Change) (string, error) {
-	if len(allChanges) == 0 {
-		return "", ErrNoChangesFoundForAuto
-	}
-
-	highestLevel := EmptyLevel
+	_, highest, err := changeLevels(config, cache, allChanges)
 
-	for _, change := range allChanges {
-		for _, kc := range config.Kinds {
-			if kc.KeyOrLabel() != change.Kind {
-				continue
-			}
+	return highest, err
+}
 
-			level, err := kc.AutoLevelForChange(cache, change)
-			if err != nil {
-				return EmptyLevel, err
-			}
+// changeAutoLevel returns the auto level of a change, using the component auto level
+// override if one is set, otherwise the kind auto level.
+// Source is empty if neither the component or kind are configured.
+func changeAutoLevel(config *Config, cache *TemplateCache, change Change) (string, string, error) {
+	cc := config.ComponentFromKeyOrLabel(change.Component)
+	if cc != nil && cc.AutoLevel != "" {
+		level, err := cc.AutoLevelForChange(cache, change)
+		return level, LevelFromComponent, err
+	}
 
-			switch level {
-			case MajorLevel:
-				// major is the highest one, so we can just return it
-				return MajorLevel, nil
-			case PatchLevel:
-				if highestLevel == EmptyLevel {
-					highestLevel = PatchLevel
-				}
-			case MinorLevel:
-				// bump to minor if we have a minor level
-				if highestLevel == PatchLevel || highestLevel == EmptyLevel {
-					highestLevel = MinorLevel
-				}
-			case EmptyLevel:
-				return EmptyLevel, ErrMissingAutoLevel
-			}
+	for _, kc := range config.Kinds {
+		if kc.KeyOrLabel() != change.Kind {
+			continue
 		}
-	}
 
-	if highestLevel == EmptyLevel {
-		return EmptyLevel, ErrNoChangesFoundForAuto
+		level, err := kc.AutoLevelForChange(cache, change)
+
+		return level, LevelFromKind, err
 	}
 
-	return highestLevel, nil
+	return EmptyLevel, "", nil
 }
 
 func GetNextVersion(
 	config *Config,
 	cache *TemplateCache,
 	partOrVersion string,
+	allowMajor bool,
 	prerelease, meta []string,
 	allChanges []Change,
 	projectKey string,
@@ -195,6 +245,10 @@ func GetNextVersion(
 			if err != nil {
 				return nil, err
 			}
+
+			if config.InitialDevelopment {
+				partOrVersion = initialDevelopmentLevel(partOrVersion, next, allowMajor)
+			}
 		}
 
 		switch partOrVersion {
@@ -230,35 +284,194 @@ func GetNextVersion(
 	return next, nil
 }
 
+// initialDevelopmentLevel lowers an auto level by one while the latest version is 0.x,
+// unless major is explicitly allowed.
+// Patch levels are never lowered.
+func initialDevelopmentLevel(level string, latest *semver.Version, allowMajor bool) string {
+	if latest.Major() > 0 {
+		return level
+	}
+
+	switch level {
+	case MajorLevel:
+		if allowMajor {
+			return MajorLevel
+		}
+
+		return MinorLevel
+	case MinorLevel:
+		return PatchLevel
+	}
+
+	return level
+}
+
 func FindChangeFiles(
 	config *Config,
 	searchPaths []string,
 ) ([]string, error) {
-	var yamlFiles []string
+	changeFiles, _, err := findUnreleasedFiles(config, searchPaths)
+
+	return changeFiles, err
+}
+
+// UnknownUnreleasedFiles returns files in the unreleased directory and search paths
+// that are not supported change fragments.
+// Hidden files, the configured version header and footer files and any known paths,
+// relative to the unreleased directory, are not unknown.
+func UnknownUnreleasedFiles(config *Config, searchPaths []string, knownPaths ...string) ([]string, error) {
+	_, otherFiles, err := findUnreleasedFiles(config, searchPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	knownPaths = append(knownPaths, config.VersionHeaderPath, config.VersionFooterPath)
+
+	var unknownFiles []string
+
+	for _, file := range otherFiles {
+		known := strings.HasPrefix(filepath.Base(file.relPath), ".") ||
+			slices.ContainsFunc(knownPaths, func(knownPath string) bool {
+				return knownPath != "" && file.relPath == filepath.Clean(knownPath)
+			})
+		if !known {
+			unknownFiles = append(unknownFiles, file.path)
+		}
+	}
+
+	return unknownFiles, nil
+}
+
+// unreleasedFile is a file found in a search directory, along with its path relative to it.
+type unreleasedFile struct {
+	path    string
+	relPath string
+}
+
+// findUnreleasedFiles returns the change fragments in the unreleased directory and search paths,
+// along with any other files that are not ignored.
+func findUnreleasedFiles(config *Config, searchPaths []string) ([]string, []unreleasedFile, error) {
+	var (
+		changeFiles []string
+		otherFiles  []unreleasedFile
+	)
 
 	// add the unreleased path to any search paths included
 	searchPaths = append(searchPaths, config.UnreleasedDir)
 
-	// read all yaml files from our search paths
+	// read all change files from our search paths, including subdirectories
 	for _, searchPath := range searchPaths {
 		rootPath := filepath.Join(config.ChangesDir, searchPath)
 
-		fileInfos, err := os.ReadDir(rootPath)
+		err := filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if path == rootPath {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(rootPath, path)
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if strings.HasPrefix(entry.Name(), ".") || isIgnoredFragmentPath(config, relPath) {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if isIgnoredFragmentPath(config, relPath) {
+				return nil
+			}
+
+			if slices.Contains(FragmentExts, strings.TrimPrefix(filepath.Ext(path), ".")) {
+				changeFiles = append(changeFiles, path)
+			} else {
+				otherFiles = append(otherFiles, unreleasedFile{path: path, relPath: relPath})
+			}
+
+			return nil
+		})
 		if err != nil {
-			return yamlFiles, err
+			return changeFiles, otherFiles, err
 		}
+	}
 
-		for _, file := range fileInfos {
-			if filepath.Ext(file.Name()) != ".yaml" {
-				continue
-			}
+	return changeFiles, otherFiles, nil
+}
+
+// isIgnoredFragmentPath returns whether a path, relative to a search directory,
+// matches any of the fragment ignore patterns by full path or base name.
+func isIgnoredFragmentPath(config *Config, relPath string) bool {
+	for _, pattern := range config.FragmentIgnore {
+		if matched, _ := filepath.Match(pattern, filepath.ToSlash(relPath)); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(pattern, filepath.Base(relPath)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveEmptyDirs removes any empty directories inside of root, including directories
+// that only contained empty directories.
+// The root directory is also removed if it is empty and removeRoot is true.
+func RemoveEmptyDirs(root string, removeRoot bool) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	remaining := len(entries)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
 
-			path := filepath.Join(rootPath, file.Name())
-			yamlFiles = append(yamlFiles, path)
+		dirPath := filepath.Join(root, entry.Name())
+
+		err = RemoveEmptyDirs(dirPath, true)
+		if err != nil {
+			return err
+		}
+
+		if exists, _ := DirExists(dirPath); !exists {
+			remaining--
 		}
 	}
 
-	return yamlFiles, nil
+	if remaining > 0 || !removeRoot {
+		return nil
+	}
+
+	return os.Remove(root)
+}
+
+// DirExists returns whether a directory exists at path.
+func DirExists(path string) (bool, error) {
+	fi, err := os.Stat(path)
+	if err == nil {
+		return fi.IsDir(), nil
+	}
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return false, err
 }
 
 func WriteNewlines(writer io.Writer, lines int) error {
@@ -303,40 +516,156 @@ func GetChanges(
 	searchPaths []string,
 	projectKey string,
 ) ([]Change, error) {
-	var changes []Change
-
 	changeFiles, err := FindChangeFiles(cfg, searchPaths)
 	if err != nil {
-		return changes, err
+		return nil, err
 	}
 
+	changes := make([]Change, 0, len(changeFiles))
+
 	for _, cf := range changeFiles {
 		c, err := LoadChange(cf)
 		if err != nil {
-			return changes, err
+			return nil, err
 		}
 
-		if len(projectKey) > 0 && c.Project != projectKey {
-			continue
+		changes = append(changes, c)
+	}
+
+	return PrepareChanges(cfg, projectKey, changes)
+}
+
+// PrepareChanges resolves the aliases and kind labels of changes for a project,
+// sorted in the order they are released.
+// Changes that are not part of the project are removed.
+func PrepareChanges(cfg *Config, projectKey string, allChanges []Change) ([]Change, error) {
+	var changes []Change
+
+	for _, c := range allChanges {
+		resolveProjectAliases(cfg, &c)
+
+		if len(projectKey) > 0 {
+			if !c.HasProject(projectKey) {
+				continue
+			}
+
+			// shared changes are treated as part of the project we are loading
+			c.Project = projectKey
 		}
 
+		ResolveAliases(cfg, &c)
+
+		changeCfg := cfg.ForChange(c)
+
 		c.Env = cfg.EnvVars()
 
-		kc := cfg.KindFromKeyOrLabel(c.KindKey)
+		kc := changeCfg.KindFromKeyOrLabel(c.KindKey)
 		if kc != nil {
 			c.KindLabel = kc.Label
-		} else if len(cfg.Kinds) > 0 {
+		} else if len(changeCfg.Kinds) > 0 {
 			return nil, fmt.Errorf("%w: '%s'", ErrKindNotFound, c.KindKey)
 		}
 
 		changes = append(changes, c)
 	}
 
-	sort.Slice(changes, ChangeLess(cfg, changes))
+	sortCfg := cfg
+	if len(projectKey) > 0 {
+		if projectCfg, projectErr := cfg.ForProject(projectKey); projectErr == nil {
+			sortCfg = projectCfg
+		}
+	}
+
+	sort.Slice(changes, ChangeLess(sortCfg, changes))
 
 	return changes, nil
 }
 
+// ResolveAliases replaces any kind, component or project aliases of a change with the
+// configured values. It returns whether any value was changed.
+// Kinds and components are resolved using the config of the change project.
+func ResolveAliases(cfg *Config, change *Change) bool {
+	changed := resolveProjectAliases(cfg, change)
+	cfg = cfg.ForChange(*change)
+
+	if kc := cfg.KindFromKeyOrLabel(change.Kind); kc != nil && kc.KeyOrLabel() != change.Kind {
+		change.Kind = kc.KeyOrLabel()
+		change.KindKey = change.Kind
+		changed = true
+	}
+
+	if cc := cfg.ComponentFromKeyOrLabel(change.Component); cc != nil && cc.KeyOrLabel() != change.Component {
+		change.Component = cc.KeyOrLabel()
+		changed = true
+	}
+
+	return changed
+}
+
+// ValidateChange returns an error if the change kind is not configured when kinds are expected,
+// or if the change component is not configured.
+func ValidateChange(cfg *Config, change Change) error {
+	if len(cfg.Kinds) > 0 && cfg.KindFromKeyOrLabel(change.Kind) == nil {
+		return fmt.Errorf("%w: '%s'", ErrKindNotFound, change.Kind)
+	}
+
+	if change.Component != "" && len(cfg.Components) > 0 && cfg.ComponentFromKeyOrLabel(change.Component) == nil {
+		return fmt.Errorf("%w: '%s'", ErrComponentNotFound, change.Component)
+	}
+
+	return nil
+}
+
+// resolveProjectAliases replaces any project aliases of a change, including shared projects,
+// with the configured project keys. It returns whether any value was changed.
+func resolveProjectAliases(cfg *Config, change *Change) bool {
+	if len(cfg.Projects) == 0 {
+		return false
+	}
+
+	changed := false
+
+	if change.Project != "" {
+		pc, err := cfg.Project(change.Project)
+		if err == nil && pc.Key != change.Project {
+			change.Project = pc.Key
+			changed = true
+		}
+	}
+
+	for i, project := range change.Projects {
+		pc, err := cfg.Project(project)
+		if err == nil && pc.Key != project {
+			change.Projects[i] = pc.Key
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// UniquePath returns a path that does not exist and is not one of the reserved paths.
+// If the path is taken, an incrementing suffix is added before the extension until a free
+// path is found, such as "added-20240102-150405-1.yaml".
+func UniquePath(path string, reserved []string) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	candidate := path
+
+	for i := 1; ; i++ {
+		exists, err := FileExists(candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists && !slices.Contains(reserved, candidate) {
+			return candidate, nil
+		}
+
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
+
 func FileExists(path string) (bool, error) {
 	fi, err := os.Stat(path)
 	if err == nil {
@@ -350,9 +679,10 @@ func FileExists(path string) (bool, error) {
 	return false, err
 }
 
-// createTempFile will create a new temporary file, writing a BOM header if we need to.
+// createTempFile will create a new temporary file, writing a BOM header if we need to
+// followed by any initial content.
 // It will return the path to that file or an error.
-func createTempFile(runtime string, ext string) (string, error) {
+func createTempFile(runtime string, ext string, content string) (string, error) {
 	file, err := os.CreateTemp("", "changie-body-txt-*."+ext)
 	if err != nil {
 		return "", err
@@ -373,9 +703,114 @@ func createTempFile(runtime string, ext string) (string, error) {
 		}
 	}
 
+	if _, err = file.WriteString(content); err != nil {
+		return "", err
+	}
+
 	return file.Name(), nil
 }
 
+// ChangedFiles is a func type returning the files changed since a base ref.
+type ChangedFiles func(baseRef string) ([]string, error)
+
+// GitChangedFiles returns the files changed since the base ref, as well as staged files,
+// using git.
+// Paths are relative to the current directory.
+// If the base ref is empty only staged files are returned.
+func GitChangedFiles(baseRef string) ([]string, error) {
+	argSets := [][]string{{"diff", "--name-only", "--relative", "--cached"}}
+	if baseRef != "" {
+		argSets = append(argSets, []string{"diff", "--name-only", "--relative", baseRef + "...HEAD"})
+	}
+
+	var files []string
+
+	for _, args := range argSets {
+		// The base ref is passed as a single argument and not run through a shell.
+		// #nosec G204
+		out, err := exec.CommandContext(context.Background(), "git", args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("finding changed files with git %s: %w", strings.Join(args, " "), err)
+		}
+
+		for _, file := range strings.Split(string(out), "\n") {
+			if file != "" && !slices.Contains(files, file) {
+				files = append(files, file)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// VersionTime returns when a version file was released, using the time in its frontmatter,
+// otherwise the date of the git commit that added the file.
+// The modification time of the file is used if it is not committed or git is unavailable.
+func VersionTime(config *Config, versionPath string) (time.Time, error) {
+	metadata, _, err := ReadVersionFile(config, versionPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if metadata != nil && !metadata.Time.IsZero() {
+		return metadata.Time, nil
+	}
+
+	// The path is passed as a single argument and not run through a shell.
+	// #nosec G204
+	out, err := exec.CommandContext(
+		context.Background(),
+		"git", "log", "--diff-filter=A", "-1", "--format=%cI", "--", versionPath,
+	).Output()
+	if err == nil {
+		if released, parseErr := time.Parse(time.RFC3339, strings.TrimSpace(string(out))); parseErr == nil {
+			return released, nil
+		}
+	}
+
+	info, err := os.Stat(versionPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// MatchAnyPath returns whether any file matches any of the path patterns.
+// Patterns use path.Match syntax, with a trailing "/**" matching every file in a directory.
+func MatchAnyPath(patterns, files []string) bool {
+	for _, pattern := range patterns {
+		for _, file := range files {
+			if matchPath(pattern, filepath.ToSlash(file)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchPath(pattern, file string) bool {
+	if dir, found := strings.CutSuffix(pattern, "/**"); found {
+		if strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+
+		// the directory itself may contain a pattern, such as "services/*/**"
+		for parent := path.Dir(file); parent != "." && parent != "/"; parent = path.Dir(parent) {
+			if matched, _ := path.Match(dir, parent); matched {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	matched, _ := path.Match(pattern, file)
+
+	return matched
+}
+
 // BuildCommand will create an exec command to run our editor.
 func BuildCommand(editorFilePath string) (EditorRunner, error) {
 	editor := os.Getenv("EDITOR")
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
+
 	"github.com/miniscruff/changie/then"
 )
 
@@ -71,6 +73,7 @@ func TestGetAllVersionsReturnsAllVersions(t *testing.T) {
 	config := &Config{
 		HeaderPath: "header.md",
 		ChangesDir: ".",
+		VersionExt: "md",
 	}
 
 	vers, err := GetAllVersions(config, false, "")
@@ -89,6 +92,7 @@ func TestGetAllVersionsReturnsAllVersionsInProject(t *testing.T) {
 	config := &Config{
 		HeaderPath: "header.md",
 		ChangesDir: ".",
+		VersionExt: "md",
 	}
 
 	vers, err := GetAllVersions(config, false, "patcher")
@@ -106,6 +110,7 @@ func TestGetLatestVersionReturnsMostRecent(t *testing.T) {
 
 	config := &Config{
 		ChangesDir: ".",
+		VersionExt: "md",
 	}
 
 	ver, err := GetLatestVersion(config, false, "")
@@ -122,6 +127,7 @@ func TestGetLatestReturnsRC(t *testing.T) {
 
 	config := &Config{
 		ChangesDir: ".",
+		VersionExt: "md",
 	}
 
 	ver, err := GetLatestVersion(config, false, "")
@@ -138,6 +144,7 @@ func TestGetLatestCanSkipRC(t *testing.T) {
 
 	config := &Config{
 		ChangesDir: ".",
+		VersionExt: "md",
 	}
 
 	ver, err := GetLatestVersion(config, true, "")
@@ -160,7 +167,7 @@ func TestErrorNextVersionBadReadDir(t *testing.T) {
 
 	config := &Config{ChangesDir: "\\."}
 
-	ver, err := GetNextVersion(config, NewTemplateCache(), "major", nil, nil, nil, "")
+	ver, err := GetNextVersion(config, NewTemplateCache(), "major", false, nil, nil, nil, "")
 	then.Equals(t, "v1.0.0", ver.Original())
 	then.Nil(t, err)
 }
@@ -171,7 +178,7 @@ func TestErrorNextVersionBadVersion(t *testing.T) {
 
 	config := &Config{ChangesDir: "."}
 
-	ver, err := GetNextVersion(config, NewTemplateCache(), "a", []string{}, []string{}, nil, "")
+	ver, err := GetNextVersion(config, NewTemplateCache(), "a", false, []string{}, []string{}, nil, "")
 	then.Equals(t, ver, nil)
 	then.Err(t, ErrBadVersionOrPart, err)
 }
@@ -251,9 +258,10 @@ func TestNextVersionOptions(t *testing.T) {
 
 			config := &Config{
 				ChangesDir: ".",
+				VersionExt: "md",
 			}
 
-			ver, err := GetNextVersion(config, NewTemplateCache(), tc.partOrVersion, tc.prerelease, tc.meta, nil, "")
+			ver, err := GetNextVersion(config, NewTemplateCache(), tc.partOrVersion, false, tc.prerelease, tc.meta, nil, "")
 			then.Nil(t, err)
 			then.Equals(t, tc.expected, ver.Original())
 		})
@@ -265,6 +273,7 @@ func TestNextVersionOptionsWithNoneAutoLevel(t *testing.T) {
 
 	config := &Config{
 		ChangesDir: ".",
+		VersionExt: "md",
 		Kinds: []KindConfig{
 			{
 				Label:     "patch",
@@ -298,7 +307,7 @@ func TestNextVersionOptionsWithNoneAutoLevel(t *testing.T) {
 		},
 	}
 
-	ver, err := GetNextVersion(config, NewTemplateCache(), "auto", nil, nil, changes, "")
+	ver, err := GetNextVersion(config, NewTemplateCache(), "auto", false, nil, nil, changes, "")
 	then.Nil(t, err)
 	then.Equals(t, "v0.3.0", ver.Original())
 }
@@ -312,6 +321,7 @@ func TestNextVersionOptionsNoneAutoLevelOnly(t *testing.T) {
 
 	config := &Config{
 		ChangesDir: ".",
+		VersionExt: "md",
 		Kinds: []KindConfig{
 			{
 				Label:     "skip",
@@ -325,9 +335,9 @@ func TestNextVersionOptionsNoneAutoLevelOnly(t *testing.T) {
 		},
 	}
 
-	ver, err := GetNextVersion(config, NewTemplateCache(), "auto", nil, nil, changes, "")
+	ver, err := GetNextVersion(config, NewTemplateCache(), "auto", false, nil, nil, changes, "")
 	then.Equals(t, ver, nil)
-	then.Err(t, ErrNoChangesFoundForAuto, err)
+	then.Err(t, ErrNoReleaseNeeded, err)
 }
 
 func TestErrorNextVersionAutoMissingKind(t *testing.T) {
@@ -338,6 +348,7 @@ func TestErrorNextVersionAutoMissingKind(t *testing.T) {
 
 	config := &Config{
 		ChangesDir: ".",
+		VersionExt: "md",
 		Kinds: []KindConfig{
 			{
 				Label: "missing",
@@ -350,7 +361,7 @@ func TestErrorNextVersionAutoMissingKind(t *testing.T) {
 		},
 	}
 
-	_, err = GetNextVersion(config, NewTemplateCache(), "auto", nil, nil, changes, "")
+	_, err = GetNextVersion(config, NewTemplateCache(), "auto", false, nil, nil, changes, "")
 	then.Err(t, ErrMissingAutoLevel, err)
 }
 
@@ -360,7 +371,7 @@ func TestErrorNextVersionBadPrerelease(t *testing.T) {
 
 	config := &Config{ChangesDir: "."}
 
-	_, err := GetNextVersion(config, NewTemplateCache(), "patch", []string{"0005"}, nil, nil, "")
+	_, err := GetNextVersion(config, NewTemplateCache(), "patch", false, []string{"0005"}, nil, nil, "")
 	then.NotNil(t, err)
 }
 
@@ -370,7 +381,7 @@ func TestErrorNextVersionBadMeta(t *testing.T) {
 
 	config := &Config{ChangesDir: "."}
 
-	_, err := GetNextVersion(config, NewTemplateCache(), "patch", nil, []string{"&&*&"}, nil, "")
+	_, err := GetNextVersion(config, NewTemplateCache(), "patch", false, nil, []string{"&&*&"}, nil, "")
 	then.NotNil(t, err)
 }
 
@@ -415,6 +426,89 @@ func TestCanFindChangeFiles(t *testing.T) {
 	then.SliceEquals(t, expected, files)
 }
 
+func TestFindChangeFilesSupportsAllFormats(t *testing.T) {
+	then.WithTempDir(t)
+
+	expected := []string{
+		filepath.Join(".chng", "unrel", "a.json"),
+		filepath.Join(".chng", "unrel", "b.toml"),
+		filepath.Join(".chng", "unrel", "c.yaml"),
+		filepath.Join(".chng", "unrel", "d.yml"),
+	}
+	for _, fp := range expected {
+		then.CreateFile(t, fp)
+	}
+
+	then.CreateFile(t, ".chng", "unrel", ".gitkeep")
+	then.CreateFile(t, ".chng", "unrel", "header.md")
+	then.CreateFile(t, ".chng", "unrel", "notes.txt")
+	then.CreateFile(t, ".chng", "unrel", "cli-header.md")
+
+	config := &Config{
+		ChangesDir:        ".chng",
+		UnreleasedDir:     "unrel",
+		VersionHeaderPath: "header.md",
+	}
+
+	files, err := FindChangeFiles(config, nil)
+	then.Nil(t, err)
+	then.SliceEquals(t, expected, files)
+
+	unknownFiles, err := UnknownUnreleasedFiles(config, nil, "cli-header.md", "")
+	then.Nil(t, err)
+	then.SliceEquals(t, []string{filepath.Join(".chng", "unrel", "notes.txt")}, unknownFiles)
+}
+
+func TestFindChangeFilesInSubdirectories(t *testing.T) {
+	then.WithTempDir(t)
+
+	expected := []string{
+		filepath.Join(".chng", "unrel", "a.yaml"),
+		filepath.Join(".chng", "unrel", "backend", "api", "b.yaml"),
+		filepath.Join(".chng", "unrel", "frontend", "c.json"),
+	}
+	for _, fp := range expected {
+		then.CreateFile(t, fp)
+	}
+
+	then.CreateFile(t, ".chng", "unrel", ".hidden", "d.yaml")
+	then.CreateFile(t, ".chng", "unrel", "drafts", "e.yaml")
+	then.CreateFile(t, ".chng", "unrel", "frontend", "f.tmp.yaml")
+
+	config := &Config{
+		ChangesDir:     ".chng",
+		UnreleasedDir:  "unrel",
+		FragmentIgnore: []string{"drafts", "*.tmp.yaml"},
+	}
+
+	files, err := FindChangeFiles(config, nil)
+	then.Nil(t, err)
+	then.SliceEquals(t, expected, files)
+}
+
+func TestRemoveEmptyDirs(t *testing.T) {
+	then.WithTempDir(t)
+	then.CreateFile(t, "root", "keep", "a.yaml")
+	then.Nil(t, os.MkdirAll(filepath.Join("root", "empty", "nested"), CreateDirMode))
+
+	then.Nil(t, RemoveEmptyDirs("root", false))
+	then.FileExists(t, "root", "keep", "a.yaml")
+	then.FileNotExists(t, "root", "empty")
+
+	then.Nil(t, os.Remove(filepath.Join("root", "keep", "a.yaml")))
+	then.Nil(t, RemoveEmptyDirs("root", false))
+	then.FileExists(t, "root")
+	then.FileNotExists(t, "root", "keep")
+
+	then.Nil(t, RemoveEmptyDirs("root", true))
+	then.FileNotExists(t, "root")
+}
+
+func TestRemoveEmptyDirsIgnoresMissingRoot(t *testing.T) {
+	then.WithTempDir(t)
+	then.Nil(t, RemoveEmptyDirs("missing", true))
+}
+
 func TestCanWriteNewLines(t *testing.T) {
 	var writer strings.Builder
 
@@ -563,6 +657,32 @@ func TestHighestAutoLevel(t *testing.T) {
 	}
 }
 
+func TestHighestAutoLevelUsesComponentOverride(t *testing.T) {
+	cfg := &Config{
+		Kinds: []KindConfig{
+			{Label: "added", AutoLevel: MinorLevel},
+		},
+		Components: []ComponentConfig{
+			{Label: "docs", AutoLevel: PatchLevel},
+			{Label: "api"},
+		},
+	}
+
+	level, err := HighestAutoLevel(cfg, NewTemplateCache(), []Change{
+		{Kind: "added", Component: "docs"},
+	})
+	then.Nil(t, err)
+	then.Equals(t, PatchLevel, level)
+
+	// components without an auto level fallback to the kind
+	level, err = HighestAutoLevel(cfg, NewTemplateCache(), []Change{
+		{Kind: "added", Component: "docs"},
+		{Kind: "added", Component: "api"},
+	})
+	then.Nil(t, err)
+	then.Equals(t, MinorLevel, level)
+}
+
 func TestErrorHighestAutoLevelMissingKindConfig(t *testing.T) {
 	cfg := &Config{
 		Kinds: []KindConfig{
@@ -677,6 +797,91 @@ func TestGetAllChangesWithProject(t *testing.T) {
 	then.Equals(t, "Added Label", changes[1].KindLabel)
 }
 
+func TestGetAllChangesResolvesAliases(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Kinds[0].Aliases = []string{"feature"}
+	cfg.Components = []ComponentConfig{{Label: "api", Aliases: []string{"backend"}}}
+	cfg.Projects = []ProjectConfig{
+		{Label: "Web", Key: "web", Aliases: []string{"frontend"}},
+	}
+
+	change := Change{Kind: "feature", Component: "backend", Project: "frontend", Body: "first"}
+	then.WriteFileTo(t, change, cfg.ChangesDir, cfg.UnreleasedDir, "0.yaml")
+
+	changes, err := GetChanges(cfg, nil, "web")
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "added", changes[0].Kind)
+	then.Equals(t, "added", changes[0].KindKey)
+	then.Equals(t, "api", changes[0].Component)
+	then.Equals(t, "web", changes[0].Project)
+}
+
+func TestGetAllChangesUsesProjectKinds(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Projects = []ProjectConfig{
+		{Key: "cli", Kinds: []KindConfig{{Key: "cmd", Label: "Command"}}},
+		{Key: "sdk"},
+	}
+
+	then.WriteFileTo(t, Change{Kind: "cmd", Project: "cli", Body: "first"}, cfg.ChangesDir, cfg.UnreleasedDir, "0.yaml")
+	then.WriteFileTo(t, Change{Kind: "added", Project: "sdk", Body: "second"}, cfg.ChangesDir, cfg.UnreleasedDir, "1.yaml")
+
+	changes, err := GetChanges(cfg, nil, "")
+	then.Nil(t, err)
+	then.SliceLen(t, 2, changes)
+	// changes from all projects are sorted using the root kinds
+	then.Equals(t, "added", changes[0].KindLabel)
+	then.Equals(t, "Command", changes[1].KindLabel)
+
+	// kinds of other projects are not valid
+	then.WriteFileTo(t, Change{Kind: "cmd", Project: "sdk", Body: "third"}, cfg.ChangesDir, cfg.UnreleasedDir, "2.yaml")
+
+	_, err = GetChanges(cfg, nil, "")
+	then.Err(t, ErrKindNotFound, err)
+}
+
+func TestGetAllChangesWithSharedProjects(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := utilsTestConfig()
+	cfg.Projects = []ProjectConfig{
+		{Key: "cli", Aliases: []string{"command"}},
+		{Key: "sdk"},
+		{Key: "docs"},
+	}
+
+	shared := Change{Kind: "added", Projects: []string{"command", "sdk"}, Body: "shared"}
+	single := Change{Kind: "added", Project: "docs", Body: "single"}
+
+	then.WriteFileTo(t, shared, cfg.ChangesDir, cfg.UnreleasedDir, "0.yaml")
+	then.WriteFileTo(t, single, cfg.ChangesDir, cfg.UnreleasedDir, "1.yaml")
+
+	changes, err := GetChanges(cfg, nil, "cli")
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "shared", changes[0].Body)
+	then.Equals(t, "cli", changes[0].Project)
+	then.SliceEquals(t, []string{"cli", "sdk"}, changes[0].Projects)
+
+	changes, err = GetChanges(cfg, nil, "docs")
+	then.Nil(t, err)
+	then.SliceLen(t, 1, changes)
+	then.Equals(t, "single", changes[0].Body)
+}
+
+func TestResolveAliasesReturnsFalseWithoutAliases(t *testing.T) {
+	cfg := utilsTestConfig()
+	change := Change{Kind: "added"}
+
+	then.False(t, ResolveAliases(cfg, &change))
+	then.Equals(t, "added", change.Kind)
+}
+
 func TestFileExists(t *testing.T) {
 	then.WithTempDir(t)
 	then.CreateFile(t, "does_exist.txt")
@@ -702,14 +907,47 @@ func TestFileExistError(t *testing.T) {
 	then.NotNil(t, err)
 }
 
+func TestUniquePathReturnsPathIfFree(t *testing.T) {
+	then.WithTempDir(t)
+
+	path, err := UniquePath("change.yaml", nil)
+	then.Nil(t, err)
+	then.Equals(t, "change.yaml", path)
+}
+
+func TestUniquePathAddsSuffixIfTaken(t *testing.T) {
+	then.WithTempDir(t)
+	then.CreateFile(t, "change.yaml")
+	then.CreateFile(t, "change-1.yaml")
+
+	path, err := UniquePath("change.yaml", []string{"change-2.yaml"})
+	then.Nil(t, err)
+	then.Equals(t, "change-3.yaml", path)
+}
+
+func TestErrorUniquePathBadPath(t *testing.T) {
+	then.WithTempDir(t)
+
+	_, err := UniquePath("\000x", nil)
+	then.NotNil(t, err)
+}
+
 func TestCreateTempFileSuccess(t *testing.T) {
-	file, err := createTempFile("windows", "txt")
+	file, err := createTempFile("windows", "txt", "")
 	defer os.Remove(file)
 
 	then.Nil(t, err)
 	then.FileContents(t, string(bom), file)
 }
 
+func TestCreateTempFileWithContent(t *testing.T) {
+	file, err := createTempFile("linux", "txt", "existing body")
+	defer os.Remove(file)
+
+	then.Nil(t, err)
+	then.FileContents(t, "existing body", file)
+}
+
 func TestBuildCommandToEditFile(t *testing.T) {
 	t.Setenv("EDITOR", "vim")
 
@@ -821,3 +1059,208 @@ func TestErrorHighestAutoLevelInvalidRender(t *testing.T) {
 	_, err := HighestAutoLevel(cfg, NewTemplateCache(), []Change{{Kind: "changed"}})
 	then.Err(t, ErrInvalidAutoLevel, err)
 }
+
+func TestMatchAnyPath(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		patterns []string
+		files    []string
+		expected bool
+	}{
+		{
+			name:     "exact file",
+			patterns: []string{"go.mod"},
+			files:    []string{"README.md", "go.mod"},
+			expected: true,
+		},
+		{
+			name:     "glob in directory",
+			patterns: []string{"proto/*.proto"},
+			files:    []string{"proto/api.proto"},
+			expected: true,
+		},
+		{
+			name:     "glob does not match nested files",
+			patterns: []string{"proto/*.proto"},
+			files:    []string{"proto/v1/api.proto"},
+			expected: false,
+		},
+		{
+			name:     "directory and all nested files",
+			patterns: []string{"services/api/**"},
+			files:    []string{"services/api/cmd/main.go"},
+			expected: true,
+		},
+		{
+			name:     "directory with glob",
+			patterns: []string{"services/*/cmd/**"},
+			files:    []string{"services/web/cmd/server/main.go"},
+			expected: true,
+		},
+		{
+			name:     "no match",
+			patterns: []string{"services/api/**"},
+			files:    []string{"services/apiv2/main.go"},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			then.Equals(t, tc.expected, MatchAnyPath(tc.patterns, tc.files))
+		})
+	}
+}
+
+func TestGitChangedFilesIncludesStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	then.WithTempDir(t)
+
+	err := exec.Command("git", "init", "-q").Run()
+	then.Nil(t, err)
+
+	then.WriteFile(t, []byte("staged"), "api", "main.go")
+	then.WriteFile(t, []byte("unstaged"), "web", "index.html")
+
+	err = exec.Command("git", "add", filepath.Join("api", "main.go")).Run()
+	then.Nil(t, err)
+
+	files, err := GitChangedFiles("")
+	then.Nil(t, err)
+	then.SliceEquals(t, []string{"api/main.go"}, files)
+
+	_, err = GitChangedFiles("missing-ref")
+	then.NotNil(t, err)
+}
+
+func TestInitialDevelopmentLevel(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		level      string
+		latest     string
+		allowMajor bool
+		expected   string
+	}{
+		{name: "major becomes minor", level: MajorLevel, latest: "v0.4.2", expected: MinorLevel},
+		{name: "minor becomes patch", level: MinorLevel, latest: "v0.4.2", expected: PatchLevel},
+		{name: "patch stays patch", level: PatchLevel, latest: "v0.4.2", expected: PatchLevel},
+		{name: "allow major", level: MajorLevel, latest: "v0.4.2", allowMajor: true, expected: MajorLevel},
+		{name: "stable major", level: MajorLevel, latest: "v1.4.2", expected: MajorLevel},
+		{name: "stable minor", level: MinorLevel, latest: "v1.4.2", expected: MinorLevel},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			level := initialDevelopmentLevel(tc.level, semver.MustParse(tc.latest), tc.allowMajor)
+			then.Equals(t, tc.expected, level)
+		})
+	}
+}
+
+func TestNextVersionInitialDevelopment(t *testing.T) {
+	then.WithTempDir(t)
+
+	_, err := os.Create("v0.4.2.md")
+	then.Nil(t, err)
+
+	config := &Config{
+		ChangesDir:         ".",
+		VersionExt:         "md",
+		InitialDevelopment: true,
+		Kinds: []KindConfig{
+			{Label: "breaking", AutoLevel: MajorLevel},
+		},
+	}
+	changes := []Change{{Kind: "breaking"}}
+
+	ver, err := GetNextVersion(config, NewTemplateCache(), "auto", false, nil, nil, changes, "")
+	then.Nil(t, err)
+	then.Equals(t, "v0.5.0", ver.Original())
+
+	ver, err = GetNextVersion(config, NewTemplateCache(), "auto", true, nil, nil, changes, "")
+	then.Nil(t, err)
+	then.Equals(t, "v1.0.0", ver.Original())
+
+	// explicit parts are not changed
+	ver, err = GetNextVersion(config, NewTemplateCache(), "major", false, nil, nil, nil, "")
+	then.Nil(t, err)
+	then.Equals(t, "v1.0.0", ver.Original())
+}
+
+func TestValidateChange(t *testing.T) {
+	cfg := &Config{
+		Kinds:      []KindConfig{{Label: "added"}},
+		Components: []ComponentConfig{{Label: "api"}},
+	}
+
+	then.Nil(t, ValidateChange(cfg, Change{Kind: "added", Component: "api"}))
+	then.Nil(t, ValidateChange(cfg, Change{Kind: "added"}))
+	then.Err(t, ErrKindNotFound, ValidateChange(cfg, Change{Kind: "fixed"}))
+	then.Err(t, ErrComponentNotFound, ValidateChange(cfg, Change{Kind: "added", Component: "web"}))
+	then.Nil(t, ValidateChange(&Config{}, Change{Kind: "anything", Component: "any"}))
+}
+
+func TestResolveAliasesUsesProjectConfig(t *testing.T) {
+	cfg := &Config{
+		Kinds: []KindConfig{{Label: "added", Aliases: []string{"new"}}},
+		Projects: []ProjectConfig{
+			{Label: "CLI", Key: "cli", Aliases: []string{"command"}, Kinds: []KindConfig{
+				{Label: "Command", Aliases: []string{"new"}},
+			}},
+		},
+	}
+
+	change := Change{Project: "command", Kind: "new"}
+	then.True(t, ResolveAliases(cfg, &change))
+	then.Equals(t, "cli", change.Project)
+	then.Equals(t, "Command", change.Kind)
+
+	rootChange := Change{Kind: "new"}
+	then.True(t, ResolveAliases(cfg, &rootChange))
+	then.Equals(t, "added", rootChange.Kind)
+}
+
+func TestGetTagVersions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	then.WithTempDir(t)
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=a", "-c", "user.email=a@b.c", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"tag", "v0.1.0"},
+		{"tag", "v0.2.0-rc1"},
+		{"tag", "not-a-version"},
+		{"tag", "api/v1.3.0"},
+		{"tag", "api/v1.2.0"},
+	} {
+		err := exec.Command("git", args...).Run()
+		then.Nil(t, err)
+	}
+
+	cfg := &Config{
+		VersionSource:            VersionSourceTags,
+		ProjectsVersionSeparator: "/",
+	}
+
+	vers, err := GetAllVersions(cfg, false, "")
+	then.Nil(t, err)
+	then.SliceLen(t, 2, vers)
+	then.Equals(t, "v0.2.0-rc1", vers[0].Original())
+	then.Equals(t, "v0.1.0", vers[1].Original())
+
+	vers, err = GetAllVersions(cfg, true, "")
+	then.Nil(t, err)
+	then.SliceLen(t, 1, vers)
+	then.Equals(t, "v0.1.0", vers[0].Original())
+
+	latest, err := GetLatestVersion(cfg, false, "api")
+	then.Nil(t, err)
+	then.Equals(t, "v1.3.0", latest.Original())
+}
+
+func TestErrorGetAllVersionsBadVersionSource(t *testing.T) {
+	_, err := GetAllVersions(&Config{VersionSource: "other"}, false, "")
+	then.Err(t, ErrVersionSource, err)
+}
@@ -0,0 +1,381 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrVersionFileFormat = errors.New("version file format must include the version exactly once")
+
+const frontmatterDelimiter = "---\n"
+
+// Markers rendered in place of the version to split the version file format.
+const (
+	versionMarker         = "\x00version\x00"
+	versionNoPrefixMarker = "\x00versionNoPrefix\x00"
+)
+
+// versionFileFormat is the version file format split around the version,
+// so versions can be mapped to file names and file names back to versions.
+type versionFileFormat struct {
+	prefix   string
+	suffix   string
+	noPrefix bool
+}
+
+func newVersionFileFormat(config *Config) (*versionFileFormat, error) {
+	format := config.VersionFileFormat
+	if format == "" {
+		format = "{{.Version}}." + config.VersionExt
+	}
+
+	rendered, err := NewTemplateCache().ExecuteString(format, BatchData{
+		Version:         versionMarker,
+		VersionNoPrefix: versionNoPrefixMarker,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Count(rendered, versionMarker)+strings.Count(rendered, versionNoPrefixMarker) != 1 {
+		return nil, fmt.Errorf("%w: %s", ErrVersionFileFormat, format)
+	}
+
+	vff := &versionFileFormat{}
+	marker := versionMarker
+
+	if strings.Contains(rendered, versionNoPrefixMarker) {
+		vff.noPrefix = true
+		marker = versionNoPrefixMarker
+	}
+
+	vff.prefix, vff.suffix, _ = strings.Cut(filepath.ToSlash(rendered), marker)
+
+	return vff, nil
+}
+
+// name returns the file name of a version, relative to the changes or project directory.
+func (vff *versionFileFormat) name(version *semver.Version) string {
+	value := version.Original()
+	if vff.noPrefix {
+		value = version.String()
+	}
+
+	return filepath.FromSlash(vff.prefix + value + vff.suffix)
+}
+
+// parse returns the version of a file name relative to the changes or project directory,
+// or false if the name does not match the format.
+func (vff *versionFileFormat) parse(name string) (*semver.Version, bool) {
+	name = filepath.ToSlash(name)
+	if !strings.HasPrefix(name, vff.prefix) || !strings.HasSuffix(name, vff.suffix) {
+		return nil, false
+	}
+
+	value := strings.TrimSuffix(strings.TrimPrefix(name, vff.prefix), vff.suffix)
+	if value == "" || strings.Contains(value, "/") {
+		return nil, false
+	}
+
+	version, err := semver.NewVersion(value)
+	if err != nil {
+		return nil, false
+	}
+
+	return version, true
+}
+
+// pattern returns a glob pattern matching every possible version file.
+func (vff *versionFileFormat) pattern() string {
+	return filepath.FromSlash(escapeGlob(vff.prefix) + "*" + escapeGlob(vff.suffix))
+}
+
+func escapeGlob(value string) string {
+	var builder strings.Builder
+
+	for _, r := range value {
+		if strings.ContainsRune(`*?[\`, r) {
+			builder.WriteRune('\\')
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// VersionFilePath returns the path of the version file for a version,
+// named using the version file format.
+func VersionFilePath(config *Config, projectKey string, version *semver.Version) (string, error) {
+	vff, err := newVersionFileFormat(config)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(config.ChangesDir, projectKey, vff.name(version)), nil
+}
+
+// VersionMetadata is the frontmatter written at the top of version files by batch
+// when version frontmatter is enabled.
+type VersionMetadata struct {
+	// Time the version was released
+	Time time.Time `yaml:"time" json:"time"`
+	// Project key of the version, if using projects
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+	// Previous released version
+	PreviousVersion string `yaml:"previousVersion,omitempty" json:"previousVersion,omitempty"`
+	// Number of changes in the version
+	Changes int `yaml:"changes" json:"changes"`
+	// Number of changes of each kind
+	Kinds map[string]int `yaml:"kinds,omitempty" json:"kinds,omitempty"`
+	// Fragments included in the version, allowing searching release history by change
+	Fragments []Change `yaml:"fragments,omitempty" json:"fragments,omitempty"`
+}
+
+// WriteTo writes the metadata as a yaml frontmatter block.
+func (vm VersionMetadata) WriteTo(writer io.Writer) (int64, error) {
+	bs, err := yaml.Marshal(&vm)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.WriteString(writer, frontmatterDelimiter+string(bs)+frontmatterDelimiter)
+
+	return int64(n), err
+}
+
+// ReadVersionFile returns the frontmatter metadata of a version file, or nil if it has none,
+// along with the release notes following the frontmatter.
+// Frontmatter is only read when version frontmatter is enabled, otherwise the entire file
+// is returned as release notes, keeping any frontmatter not written by changie.
+func ReadVersionFile(config *Config, versionPath string) (*VersionMetadata, []byte, error) {
+	bs, err := os.ReadFile(versionPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !config.VersionFrontmatter || !bytes.HasPrefix(bs, []byte(frontmatterDelimiter)) {
+		return nil, bs, nil
+	}
+
+	rest := bs[len(frontmatterDelimiter)-1:]
+
+	end := bytes.Index(rest, []byte("\n"+frontmatterDelimiter))
+	if end < 0 {
+		return nil, bs, nil
+	}
+
+	var metadata VersionMetadata
+
+	err = yaml.Unmarshal(rest[:end+1], &metadata)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading frontmatter of '%s': %w", versionPath, err)
+	}
+
+	return &metadata, rest[end+1+len(frontmatterDelimiter):], nil
+}
+
+// AppendVersionFile appends the release notes of a version file, without any frontmatter
+// written by changie.
+func AppendVersionFile(config *Config, writer io.Writer, versionPath string) error {
+	_, notes, err := ReadVersionFile(config, versionPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(notes)
+
+	return err
+}
+
+// ReleasedVersion is a version along with when it was released and its frontmatter, if any.
+type ReleasedVersion struct {
+	// Version of the release
+	Version string `json:"version"`
+	// Time of the release
+	Time time.Time `json:"time"`
+	// Frontmatter metadata of the version file, nil if the file has none
+	Metadata *VersionMetadata `json:"metadata,omitempty"`
+}
+
+// GetReleasedVersions returns every version with a version file, along with when it was released
+// and its frontmatter, sorted newest first.
+func GetReleasedVersions(config *Config, skipPrereleases bool, projectKey string) ([]ReleasedVersion, error) {
+	vers, err := GetFileVersions(config, skipPrereleases, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]ReleasedVersion, 0, len(vers))
+
+	for _, ver := range vers {
+		versionPath, err := VersionFilePath(config, projectKey, ver)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata, _, err := ReadVersionFile(config, versionPath)
+		if err != nil {
+			return nil, err
+		}
+
+		released, err := VersionTime(config, versionPath)
+		if err != nil {
+			return nil, err
+		}
+
+		releases = append(releases, ReleasedVersion{
+			Version:  ver.Original(),
+			Time:     released,
+			Metadata: metadata,
+		})
+	}
+
+	return releases, nil
+}
+
+// OrderVersions returns versions sorted by order, or the configured version order if empty.
+// Versions released at the same time are sorted by semantic version in the same direction.
+func OrderVersions(
+	config *Config,
+	projectKey, order string,
+	versions []*semver.Version,
+) ([]*semver.Version, error) {
+	if order == "" {
+		order = config.VersionOrder
+	}
+
+	ordered := slices.Clone(versions)
+
+	switch order {
+	case "", VersionOrderDescending:
+		sort.Sort(sort.Reverse(semver.Collection(ordered)))
+	case VersionOrderAscending:
+		sort.Sort(semver.Collection(ordered))
+	case VersionOrderDateDescending, VersionOrderDateAscending:
+		released := make(map[*semver.Version]time.Time, len(ordered))
+
+		for _, version := range ordered {
+			versionPath, err := VersionFilePath(config, projectKey, version)
+			if err != nil {
+				return nil, err
+			}
+
+			released[version], err = VersionTime(config, versionPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		sort.Sort(semver.Collection(ordered))
+		slices.SortStableFunc(ordered, func(a, b *semver.Version) int {
+			return released[a].Compare(released[b])
+		})
+
+		if order == VersionOrderDateDescending {
+			slices.Reverse(ordered)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrVersionOrder, order)
+	}
+
+	return ordered, nil
+}
+
+// ChangelogVersions returns the changelog data of versions, in the same order,
+// linking each version to the versions released before and after it.
+func ChangelogVersions(config *Config, projectKey string, versions []*semver.Version) ([]ChangelogVersion, error) {
+	sorted := slices.Clone(versions)
+	sort.Sort(semver.Collection(sorted))
+
+	changelogVersions := make([]ChangelogVersion, 0, len(versions))
+
+	for _, version := range versions {
+		anchor, err := versionAnchor(config, projectKey, version)
+		if err != nil {
+			return nil, err
+		}
+
+		cv := ChangelogVersion{
+			Version:         version.Original(),
+			VersionNoPrefix: version.String(),
+			Anchor:          anchor,
+		}
+
+		i := slices.IndexFunc(sorted, version.Equal)
+		if i > 0 {
+			cv.PreviousVersion = sorted[i-1].Original()
+		}
+
+		if i < len(sorted)-1 {
+			cv.NextVersion = sorted[i+1].Original()
+		}
+
+		changelogVersions = append(changelogVersions, cv)
+	}
+
+	return changelogVersions, nil
+}
+
+// VersionAnchor returns the explicit anchor written before a version when
+// changelog version anchors are enabled, such as v120 for v1.2.0.
+func VersionAnchor(version *semver.Version) string {
+	return headingAnchor(version.Original())
+}
+
+// versionAnchor returns the anchor linking to a version in the merged changelog.
+// Without explicit anchors, this is the anchor of the first heading in the version file,
+// falling back to the version if the file has no heading.
+func versionAnchor(config *Config, projectKey string, version *semver.Version) (string, error) {
+	if config.ChangelogVersionAnchors {
+		return VersionAnchor(version), nil
+	}
+
+	versionPath, err := VersionFilePath(config, projectKey, version)
+	if err != nil {
+		return "", err
+	}
+
+	_, notes, err := ReadVersionFile(config, versionPath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(notes), "\n") {
+		heading := strings.TrimLeft(line, "#")
+		if len(heading) < len(line) && strings.HasPrefix(heading, " ") {
+			return headingAnchor(strings.TrimSpace(heading)), nil
+		}
+	}
+
+	return VersionAnchor(version), nil
+}
+
+// headingAnchor returns the anchor GitHub generates for a heading,
+// keeping lowercase letters, numbers, hyphens and underscores, with spaces as hyphens.
+func headingAnchor(heading string) string {
+	var builder strings.Builder
+
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			builder.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r):
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
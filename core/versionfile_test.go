@@ -0,0 +1,222 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/miniscruff/changie/then"
+)
+
+func TestVersionFilePath(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		format   string
+		project  string
+		expected string
+	}{
+		{name: "default", expected: filepath.Join("chgs", "v1.2.0.md")},
+		{name: "prefix", format: "release-{{.Version}}.md", expected: filepath.Join("chgs", "release-v1.2.0.md")},
+		{name: "no prefix", format: "{{.VersionNoPrefix}}.md", expected: filepath.Join("chgs", "1.2.0.md")},
+		{name: "directory", format: "{{.Version}}/notes.md", expected: filepath.Join("chgs", "v1.2.0", "notes.md")},
+		{name: "project", project: "api", expected: filepath.Join("chgs", "api", "v1.2.0.md")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{ChangesDir: "chgs", VersionExt: "md", VersionFileFormat: tc.format}
+
+			path, err := VersionFilePath(cfg, tc.project, semver.MustParse("v1.2.0"))
+			then.Nil(t, err)
+			then.Equals(t, tc.expected, path)
+		})
+	}
+}
+
+func TestErrorVersionFilePathWithoutVersion(t *testing.T) {
+	for _, format := range []string{
+		"notes.md",
+		"{{.Version}}/{{.Version}}.md",
+	} {
+		cfg := &Config{ChangesDir: "chgs", VersionFileFormat: format}
+
+		_, err := VersionFilePath(cfg, "", semver.MustParse("v1.2.0"))
+		then.Err(t, ErrVersionFileFormat, err)
+
+		_, err = GetFileVersions(cfg, false, "")
+		then.Err(t, ErrVersionFileFormat, err)
+	}
+}
+
+func TestGetFileVersionsUsesVersionFileFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format string
+		files  []string
+	}{
+		{
+			name:   "prefix",
+			format: "release-{{.Version}}.md",
+			files:  []string{"release-v0.1.0.md", "release-v0.2.0.md", "v0.3.0.md", "release-notes.md"},
+		},
+		{
+			name:   "directory",
+			format: "{{.Version}}/notes.md",
+			files:  []string{"v0.1.0/notes.md", "v0.2.0/notes.md", "v0.3.0/other.md", "v0.3.0.md"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			then.WithTempDir(t)
+
+			for _, file := range tc.files {
+				then.CreateFile(t, "chgs", file)
+			}
+
+			cfg := &Config{ChangesDir: "chgs", VersionExt: "md", VersionFileFormat: tc.format}
+
+			vers, err := GetFileVersions(cfg, false, "")
+			then.Nil(t, err)
+			then.SliceLen(t, 2, vers)
+			then.Equals(t, "v0.2.0", vers[0].Original())
+			then.Equals(t, "v0.1.0", vers[1].Original())
+		})
+	}
+}
+
+func TestReadVersionFile(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := &Config{VersionFrontmatter: true}
+
+	then.WriteFile(t, []byte("---\ntime: 2026-01-02T03:04:05Z\nchanges: 2\n---\n## v0.1.0\n"), "with.md")
+	then.WriteFile(t, []byte("## v0.1.0\n---\n"), "without.md")
+	then.WriteFile(t, []byte("---\nnot closed\n"), "unclosed.md")
+	then.WriteFile(t, []byte("---\nchanges: [\n---\n"), "invalid.md")
+
+	metadata, notes, err := ReadVersionFile(cfg, "with.md")
+	then.Nil(t, err)
+	then.Equals(t, 2, metadata.Changes)
+	then.Equals(t, "2026-01-02T03:04:05Z", metadata.Time.Format(time.RFC3339))
+	then.Equals(t, "## v0.1.0\n", string(notes))
+
+	released, err := VersionTime(cfg, "with.md")
+	then.Nil(t, err)
+	then.True(t, metadata.Time.Equal(released))
+
+	for _, name := range []string{"without.md", "unclosed.md"} {
+		metadata, notes, err = ReadVersionFile(cfg, name)
+		then.Nil(t, err)
+		then.Equals(t, nil, metadata)
+
+		contents, _ := os.ReadFile(name)
+		then.Equals(t, string(contents), string(notes))
+	}
+
+	_, _, err = ReadVersionFile(cfg, "invalid.md")
+	then.NotNil(t, err)
+}
+
+func TestReadVersionFileKeepsFrontmatterWhenDisabled(t *testing.T) {
+	then.WithTempDir(t)
+
+	contents := "---\ntitle: Release\nlayout: post\n---\n## v0.1.0\n"
+	then.WriteFile(t, []byte(contents), "jekyll.md")
+
+	metadata, notes, err := ReadVersionFile(&Config{}, "jekyll.md")
+	then.Nil(t, err)
+	then.Equals(t, nil, metadata)
+	then.Equals(t, contents, string(notes))
+}
+
+func TestChangelogVersions(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := &Config{ChangesDir: "chgs", VersionExt: "md"}
+
+	then.WriteFile(t, []byte("---\nchanges: 1\n---\n## v1.2.0 - 2024-01-02\n"), "chgs", "v1.2.0.md")
+	then.WriteFile(t, []byte("notes without a heading\n"), "chgs", "v1.0.0.md")
+	then.WriteFile(t, []byte("#not a heading\n### v1.1.0-rc.1 (Beta)\n"), "chgs", "v1.1.0-rc.1.md")
+
+	versions := []*semver.Version{
+		semver.MustParse("v1.2.0"),
+		semver.MustParse("v1.0.0"),
+		semver.MustParse("v1.1.0-rc.1"),
+	}
+
+	changelogVersions, err := ChangelogVersions(cfg, "", versions)
+	then.Nil(t, err)
+	then.SliceEquals(t, []ChangelogVersion{
+		{Version: "v1.2.0", VersionNoPrefix: "1.2.0", PreviousVersion: "v1.1.0-rc.1", Anchor: "v120---2024-01-02"},
+		{Version: "v1.0.0", VersionNoPrefix: "1.0.0", NextVersion: "v1.1.0-rc.1", Anchor: "v100"},
+		{
+			Version:         "v1.1.0-rc.1",
+			VersionNoPrefix: "1.1.0-rc.1",
+			PreviousVersion: "v1.0.0",
+			NextVersion:     "v1.2.0",
+			Anchor:          "v110-rc1-beta",
+		},
+	}, changelogVersions)
+
+	cfg.ChangelogVersionAnchors = true
+
+	changelogVersions, err = ChangelogVersions(cfg, "", versions)
+	then.Nil(t, err)
+	then.Equals(t, "v120", changelogVersions[0].Anchor)
+}
+
+func TestOrderVersions(t *testing.T) {
+	then.WithTempDir(t)
+
+	cfg := &Config{ChangesDir: "chgs", VersionExt: "md"}
+
+	for _, release := range []struct {
+		version string
+		date    time.Time
+	}{
+		{"v1.9.4", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"v2.1.0", time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)},
+		{"v1.9.5", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+	} {
+		var builder bytes.Buffer
+
+		_, err := VersionMetadata{Time: release.date}.WriteTo(&builder)
+		then.Nil(t, err)
+		then.WriteFile(t, builder.Bytes(), "chgs", release.version+".md")
+	}
+
+	versions, err := GetFileVersions(cfg, false, "")
+	then.Nil(t, err)
+
+	for _, tc := range []struct {
+		order    string
+		expected []string
+	}{
+		{"", []string{"v2.1.0", "v1.9.5", "v1.9.4"}},
+		{VersionOrderAscending, []string{"v1.9.4", "v1.9.5", "v2.1.0"}},
+		{VersionOrderDateDescending, []string{"v1.9.5", "v2.1.0", "v1.9.4"}},
+		{VersionOrderDateAscending, []string{"v1.9.4", "v2.1.0", "v1.9.5"}},
+	} {
+		t.Run(tc.order, func(t *testing.T) {
+			ordered, err := OrderVersions(cfg, "", tc.order, versions)
+			then.Nil(t, err)
+
+			originals := make([]string, 0, len(ordered))
+			for _, version := range ordered {
+				originals = append(originals, version.Original())
+			}
+
+			then.SliceEquals(t, tc.expected, originals)
+		})
+	}
+
+	cfg.VersionOrder = VersionOrderAscending
+
+	ordered, err := OrderVersions(cfg, "", "", versions)
+	then.Nil(t, err)
+	then.Equals(t, "v1.9.4", ordered[0].Original())
+
+	_, err = OrderVersions(cfg, "", "newest", versions)
+	then.Err(t, ErrVersionOrder, err)
+}
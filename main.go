@@ -16,6 +16,6 @@ func main() {
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }